@@ -1,215 +1,1161 @@
 package main
 
 import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"image"
+	"image/png"
+	"io"
 	"log"
+	"math"
 	"net"
+	"net/http"
 	"net/rpc"
+	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"uk.ac.bris.cs/gameoflife/stubs"
+	"uk.ac.bris.cs/gameoflife/util"
 )
 
+// tileSize is the side length of the square tiles used for active-tile tracking.
+const tileSize = 32
+
+// workerConn sends calls to one worker. Both *rpc.Client and *stubs.GRPCClient
+// implement it.
+type workerConn interface {
+	Go(serviceMethod string, args, reply interface{}, done chan *rpc.Call) *rpc.Call
+	Call(serviceMethod string, args, reply interface{}) error
+	Close() error
+}
+
+// transferStats counts the cell bytes of the current run before and after
+// encoding, between the broker and its workers and between the broker and
+// controllers. With workers over gRPC they count whole worker messages; see
+// stubs.GRPCClient.Stats.
+type transferStats struct {
+	LastTurnRawBytes    int64
+	LastTurnWireBytes   int64
+	TotalRawBytes       int64
+	TotalWireBytes      int64
+	SavedBytesPerTurn   int64
+	ControllerRawBytes  int64
+	ControllerWireBytes int64
+}
+
+// workerBlock keeps the request and response exchanged with one worker, so that
+// gob encodes from and decodes into the same buffers turn after turn. worker is
+// the index of the worker the block was sent to and call its pending call.
+type workerBlock struct {
+	request  stubs.WorkerRequest
+	response stubs.WorkerResponse
+	worker   int
+	call     *rpc.Call
+}
+
+var (
+	// errTurnFailed means a worker failed or timed out during a turn. It has
+	// been left out and the turn can be retried on the rest.
+	errTurnFailed = errors.New("a worker failed during the turn")
+	// errTurnCancelled means the run was stopped during a turn.
+	errTurnCancelled = errors.New("turn cancelled")
+	errNoWorkers     = errors.New("no workers left")
+)
+
+// pendingEdit is an edit waiting for the turn in flight to complete. apply
+// checks the edit and then calls set for every cell it writes.
+type pendingEdit struct {
+	apply func(set func(x, y int, alive bool)) error
+	res   *stubs.EditResponse
+	done  chan error
+}
+
+// Broker double-buffers the world: world holds the current generation, flattened
+// row by row, and next receives the following one before the two are swapped.
+// Workers keep no cells between turns: each turn sends every worker its block
+// with a halo and gathers the block back into next, so world is always the
+// whole generation and reads such as GetRegion never need a worker. tileTurns records the last turn in which each tile changed, so that
+// GetWorldDelta can answer for any turn of the current run. live lists the
+// workers that have not failed. cancel is closed to abandon the turn in flight,
+// and simDone when the run's goroutine exits. Edits made while inTurn wait in
+// pending until the turn in flight completes; tileEdits records the last of the
+// run's edits, numbered by edits, to change each tile.
 type Broker struct {
-	mu          sync.Mutex
-	workers     []*rpc.Client
-	workerAddrs []string
-	world       [][]uint8
-	height      int
-	width       int
-	turn        int
-	totalTurns  int
-	stop        bool
-	processing  bool
-	paused      bool
-	shutdown    bool
-}
-
-func (b *Broker) connectToWorkers(workerAddrs []string) error {
+	mu           sync.Mutex
+	workers      []workerConn
+	workerAddrs  []string
+	live         []int
+	timeout      time.Duration
+	cancel       chan struct{}
+	simDone      chan struct{}
+	runErr       error
+	until        *stubs.StopWatch
+	stopReason   string
+	stopPeriod   int
+	world        []uint8
+	next         []uint8
+	height       int
+	width        int
+	turn         int
+	totalTurns   int
+	threads      int
+	seed         uint64
+	probability  float64
+	stop         bool
+	processing   bool
+	paused       bool
+	steps        int
+	speed        float64
+	lastTurnAt   time.Time
+	shutdown     bool
+	tilesX       int
+	tilesY       int
+	dirty        []bool
+	changed      []bool
+	tileTurns    []int
+	tileEdits    []int
+	edits        int
+	inTurn       bool
+	pending      []pendingEdit
+	blocks       []workerBlock
+	done         chan *rpc.Call
+	run          int
+	turnDone     chan struct{}
+	encodings    []string
+	maxCells     int
+	outDir       string
+	workerHellos []stubs.HelloResponse
+	workerStats  []*stubs.WireStats
+	transfer     transferStats
+	tokens       stubs.Tokens
+	events       stubs.EventLog
+}
+
+// methodRoles is the role each broker method needs when the broker checks
+// tokens. SaveWorld is the viewer's save command, which writes to the broker's disk.
+var methodRoles = map[string]string{
+	"Hello":          stubs.RoleObserver,
+	"GetWorld":       stubs.RoleObserver,
+	"GetWorldDelta":  stubs.RoleObserver,
+	"GetAliveCells":  stubs.RoleObserver,
+	"Subscribe":      stubs.RoleObserver,
+	"GetRegion":      stubs.RoleObserver,
+	"Process":        stubs.RoleController,
+	"SetCells":       stubs.RoleController,
+	"ClearRegion":    stubs.RoleController,
+	"PastePattern":   stubs.RoleController,
+	"Pause":          stubs.RoleController,
+	"Step":           stubs.RoleController,
+	"SetSpeed":       stubs.RoleController,
+	"Resume":         stubs.RoleController,
+	"StopProcessing": stubs.RoleController,
+	"Shutdown":       stubs.RoleController,
+	"SaveWorld":      stubs.RoleController,
+}
+
+// authorize returns the role of token and an error if it may not call method.
+func (b *Broker) authorize(token, method string) (string, error) {
+	return b.tokens.Authorize(token, method, methodRoles[method])
+}
+
+// connectToWorkers dials every worker, over gRPC when useGRPC is set and over
+// net/rpc with the framed worker codec otherwise, and agrees on how to encode
+// cells with each of them. A non-nil tlsConfig secures every connection.
+func (b *Broker) connectToWorkers(workerAddrs []string, useGRPC bool, tlsConfig *tls.Config) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
 	b.workerAddrs = workerAddrs
-	b.workers = make([]*rpc.Client, len(workerAddrs))
+	b.workers = make([]workerConn, len(workerAddrs))
+	b.workerStats = make([]*stubs.WireStats, len(workerAddrs))
+	b.live = b.live[:0]
 
 	for i, addr := range workerAddrs {
-		client, err := rpc.Dial("tcp", addr)
+		var setEncoding func(string)
+		if useGRPC {
+			var opts []grpc.DialOption
+			if tlsConfig != nil {
+				opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+			}
+			client, err := stubs.DialGRPC(addr, opts...)
+			if err != nil {
+				return fmt.Errorf("failed to connect to worker at %s: %v", addr, err)
+			}
+			b.workers[i] = client
+			b.workerStats[i] = client.Stats()
+			setEncoding = client.SetEncoding
+		} else {
+			conn, err := stubs.Dial(addr, tlsConfig, b.timeout)
+			if err != nil {
+				return fmt.Errorf("failed to connect to worker at %s: %v", addr, err)
+			}
+			codec := stubs.NewWorkerClientCodec(conn)
+			b.workers[i] = rpc.NewClientWithCodec(codec)
+			b.workerStats[i] = codec.Stats()
+			setEncoding = codec.SetEncoding
+		}
+
+		hello := &stubs.HelloRequest{
+			Version:   stubs.ProtocolVersion,
+			Rules:     stubs.Rules,
+			Encodings: b.encodings,
+			MaxCells:  stubs.MaxCells,
+		}
+		res, err := stubs.SayHello(stubs.CallerTimeout(b.workers[i], b.timeout), stubs.WorkerHello, "worker at "+addr, hello)
 		if err != nil {
-			return fmt.Errorf("failed to connect to worker at %s: %v", addr, err)
+			return err
+		}
+		b.live = append(b.live, i)
+		setEncoding(res.Encoding)
+		b.workerHellos = append(b.workerHellos, *res)
+		if res.MaxCells > 0 && (b.maxCells == 0 || res.MaxCells < b.maxCells) {
+			b.maxCells = res.MaxCells
+		}
+	}
+
+	return nil
+}
+
+// workerFault leaves the worker at index i out of later turns and closes its
+// connection, which ends any call still pending on it. The caller must hold b.mu.
+func (b *Broker) workerFault(i int, err error) {
+	for j, live := range b.live {
+		if live == i {
+			log.Printf("Worker at %s failed, leaving it out: %v", b.workerAddrs[i], err)
+			b.events.Add(stubs.RunEvent{Kind: stubs.EventWorkerFailed, Run: b.run, CompletedTurns: b.turn, Worker: b.workerAddrs[i], Error: err.Error()})
+			b.live = append(b.live[:j], b.live[j+1:]...)
+			_ = b.workers[i].Close()
+			return
+		}
+	}
+}
+
+// isLive reports whether the worker at index i has not failed. The caller must
+// hold b.mu.
+func (b *Broker) isLive(i int) bool {
+	for _, live := range b.live {
+		if live == i {
+			return true
 		}
-		b.workers[i] = client
 	}
+	return false
+}
+
+// cancelTurn abandons the turn in flight, if any. The caller must hold b.mu.
+func (b *Broker) cancelTurn() {
+	if b.cancel != nil {
+		close(b.cancel)
+		b.cancel = nil
+	}
+}
 
+// workersHave reports whether every worker advertised feature.
+func (b *Broker) workersHave(feature string) bool {
+	for _, hello := range b.workerHellos {
+		if !stubs.HasFeature(hello.Features, feature) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hello checks that a controller speaks the broker's protocol and describes
+// what the broker and its workers can do.
+func (b *Broker) Hello(req *stubs.HelloRequest, res *stubs.HelloResponse) error {
+	if err := stubs.CheckHello("controller", req); err != nil {
+		return err
+	}
+	role, err := b.authorize(req.Token, "Hello")
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	*res = stubs.HelloResponse{
+		Version:  stubs.ProtocolVersion,
+		Rules:    stubs.Rules,
+		Encoding: stubs.ChooseEncoding(req.Encodings, b.encodings),
+		MaxCells: b.maxCells,
+		Features: []string{stubs.FeatureWorldDelta, stubs.FeatureEvents},
+		Role:     role,
+	}
+	if b.workersHave(stubs.FeatureNoisy) {
+		res.Features = append(res.Features, stubs.FeatureNoisy)
+	}
 	return nil
 }
 
 func (b *Broker) Process(req *stubs.EngineRequest, res *stubs.EngineResponse) error {
+	if _, err := b.authorize(req.Token, "Process"); err != nil {
+		return err
+	}
+	if req.ImageWidth <= 0 || req.ImageHeight <= 0 {
+		return badRequest(fmt.Errorf("invalid world size %dx%d", req.ImageWidth, req.ImageHeight))
+	}
+	if req.Encoding == stubs.EncodingRaw {
+		if len(req.World) != req.ImageHeight {
+			return badRequest(fmt.Errorf("world has %d rows, expected %d", len(req.World), req.ImageHeight))
+		}
+		for y, row := range req.World {
+			if len(row) != req.ImageWidth {
+				return badRequest(fmt.Errorf("row %d of the world has %d cells, expected %d", y, len(row), req.ImageWidth))
+			}
+		}
+	}
 	b.mu.Lock()
-	if b.processing {
-		// Previous simulation is running; stop it
+	if b.maxCells > 0 && req.ImageWidth*req.ImageHeight > b.maxCells {
+		b.mu.Unlock()
+		return badRequest(fmt.Errorf("a %dx%d world is larger than the %d cells this broker accepts", req.ImageWidth, req.ImageHeight, b.maxCells))
+	}
+	if err := req.Until.Validate(); err != nil {
+		b.mu.Unlock()
+		return badRequest(err)
+	}
+	if req.Probability > 0 && req.Probability < 1 && !b.workersHave(stubs.FeatureNoisy) {
+		b.mu.Unlock()
+		return conflict(errors.New("noisy Life was requested, but not every worker supports it"))
+	}
+	if b.simDone != nil {
+		// Stop the previous simulation, if it is still running, and wait for it
 		b.stop = true
-		// Wait for it to finish
+		b.cancelTurn()
+		done := b.simDone
 		b.mu.Unlock()
-		b.waitForProcessingToFinish()
+		<-done
 		b.mu.Lock()
 	}
-	b.world = req.World
+	world := make([]uint8, req.ImageWidth*req.ImageHeight)
+	wireBytes := int64(len(world))
+	if req.Encoding != stubs.EncodingRaw {
+		var c stubs.Compressor
+		if _, err := c.Decode(world, req.Encoding, req.Cells, len(world)); err != nil {
+			b.mu.Unlock()
+			return badRequest(err)
+		}
+		wireBytes = int64(len(req.Cells))
+	} else {
+		for y := 0; y < req.ImageHeight; y++ {
+			copy(world[y*req.ImageWidth:(y+1)*req.ImageWidth], req.World[y])
+		}
+	}
 	b.height = req.ImageHeight
 	b.width = req.ImageWidth
+	b.world = world
+	b.next = make([]uint8, b.width*b.height)
+	b.transfer = transferStats{ControllerRawBytes: int64(len(world)), ControllerWireBytes: wireBytes}
 	b.turn = 0
 	b.totalTurns = req.Turns
+	b.threads = req.Threads
+	b.seed = req.Seed
+	b.probability = req.Probability
 	b.stop = false
 	b.processing = true
 	b.paused = false
+	b.steps = 0
+	b.speed = 0
 	b.shutdown = false
+	b.runErr = nil
+	b.until = stubs.NewStopWatch(req.Until, world)
+	b.stopReason, b.stopPeriod = "", 0
+	b.resetTiles()
+	b.run++
+	b.cancel = make(chan struct{})
+	b.simDone = make(chan struct{})
+	b.notifyTurn()
+	b.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: b.run, State: stubs.StateExecuting})
+	go b.runSimulation(b.simDone)
+	go b.reportAlive(b.run, b.simDone)
+	res.Run = b.run
 	b.mu.Unlock()
 
-	go b.runSimulation()
-
 	res.World = nil
 	res.CompletedTurns = 0
 	return nil
 }
 
-func (b *Broker) runSimulation() {
-	for t := 0; t < b.totalTurns; t++ {
+// reportAlive logs the number of alive cells of run every stubs.AliveInterval
+// until done is closed.
+func (b *Broker) reportAlive(run int, done <-chan struct{}) {
+	ticker := time.NewTicker(stubs.AliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			if b.run == run {
+				b.events.Add(stubs.RunEvent{Kind: stubs.EventAlive, Run: run, CompletedTurns: b.turn, CellsCount: b.aliveCount()})
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+// resetTiles marks every tile dirty so that the next turn recomputes the whole
+// world, and forgets when tiles last changed.
+func (b *Broker) resetTiles() {
+	b.tilesX = (b.width + tileSize - 1) / tileSize
+	b.tilesY = (b.height + tileSize - 1) / tileSize
+	b.dirty = make([]bool, b.tilesX*b.tilesY)
+	b.changed = make([]bool, b.tilesX*b.tilesY)
+	b.tileTurns = make([]int, b.tilesX*b.tilesY)
+	b.tileEdits = make([]int, b.tilesX*b.tilesY)
+	b.edits = 0
+	for i := range b.dirty {
+		b.dirty[i] = true
+	}
+}
+
+// expandTiles marks a tile of dirty if it or any of its eight (wrapping)
+// neighbours is marked in changed.
+func (b *Broker) expandTiles(dirty, changed []bool) {
+	for i := range dirty {
+		dirty[i] = false
+	}
+	for tile, c := range changed {
+		if c {
+			b.markAround(dirty, tile)
+		}
+	}
+}
+
+// markAround marks tile and its eight (wrapping) neighbours in dirty.
+func (b *Broker) markAround(dirty []bool, tile int) {
+	tx, ty := tile%b.tilesX, tile/b.tilesX
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			ny := (ty + dy + b.tilesY) % b.tilesY
+			nx := (tx + dx + b.tilesX) % b.tilesX
+			dirty[ny*b.tilesX+nx] = true
+		}
+	}
+}
+
+// runSimulation runs the turns of a run and closes done when it returns. A turn
+// in which a worker failed is retried on the remaining workers. A paused run
+// still completes the turns Step asked for. Turns are held back to keep to the
+// speed set by SetSpeed, and the run ends early once a stop condition holds.
+func (b *Broker) runSimulation(done chan struct{}) {
+	defer close(done)
+	b.mu.Lock()
+	run := b.run
+	remaining, timed := b.until.Remaining()
+	b.mu.Unlock()
+	if timed {
+		timer := time.AfterFunc(remaining, func() { b.timeOut(run) })
+		defer timer.Stop()
+	}
+	for t := 0; t < b.totalTurns; {
 		b.mu.Lock()
+		for !b.stop && (b.paused && b.steps == 0 || b.untilNextTurn() > 0) {
+			// Wait until resumed, stepped or the next turn is due
+			wait := b.untilNextTurn()
+			if wait <= 0 || wait > 100*time.Millisecond {
+				wait = 100 * time.Millisecond
+			}
+			b.mu.Unlock()
+			time.Sleep(wait)
+			b.mu.Lock()
+		}
 		if b.stop || b.shutdown {
 			b.processing = false
 			b.mu.Unlock()
 			break
 		}
-		for b.paused {
-			// Wait until resumed
-			b.mu.Unlock()
-			time.Sleep(100 * time.Millisecond)
-			b.mu.Lock()
-		}
 		b.mu.Unlock()
 
 		// Distribute work to workers
 		err := b.distributeWork()
+		if err == errTurnCancelled {
+			break
+		}
+		if err == errTurnFailed {
+			continue
+		}
 		if err != nil {
 			log.Println("Error distributing work:", err)
-			return
+			b.mu.Lock()
+			b.runErr = err
+			b.mu.Unlock()
+			break
 		}
-
+		t++
 		b.mu.Lock()
-		b.turn = t + 1
+		b.lastTurnAt = time.Now()
+		alive := -1
+		if b.until.NeedsAlive() {
+			alive = b.aliveCount()
+		}
+		if reason, period := b.until.Check(b.world, alive); reason != "" {
+			b.stopReason, b.stopPeriod = reason, period
+			b.mu.Unlock()
+			break
+		}
+		if b.steps > 0 {
+			b.steps--
+			if b.steps == 0 {
+				b.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: b.run, CompletedTurns: b.turn, State: stubs.StatePaused})
+			}
+		}
 		b.mu.Unlock()
 	}
 
 	b.mu.Lock()
 	b.processing = false
+	b.notifyTurn()
+	finished := stubs.RunEvent{
+		Kind:           stubs.EventFinished,
+		Run:            b.run,
+		CompletedTurns: b.turn,
+		CellsCount:     b.aliveCount(),
+		Reason:         b.stopReason,
+		Period:         b.stopPeriod,
+	}
+	if b.runErr != nil {
+		finished.Error = b.runErr.Error()
+	}
+	b.events.Add(finished)
+	if b.stopReason != "" {
+		log.Printf("Run stopped after %d turns: %s", b.turn, stubs.DescribeStop(b.stopReason, b.stopPeriod))
+	}
+	if b.turn > 0 {
+		log.Printf("Run finished after %d turns; workers exchanged %d cell bytes as %d, saving %d bytes per turn",
+			b.turn, b.transfer.TotalRawBytes, b.transfer.TotalWireBytes, (b.transfer.TotalRawBytes-b.transfer.TotalWireBytes)/int64(b.turn))
+	}
 	b.mu.Unlock()
 }
 
-func (b *Broker) distributeWork() error {
+// timeOut stops run once its Timeout has passed, abandoning the turn in flight
+// so that a slow worker cannot hold it up.
+func (b *Broker) timeOut(run int) {
 	b.mu.Lock()
-	// Divide world into slices
-	numWorkers := len(b.workers)
-	rowsPerWorker := b.height / numWorkers
-	remainder := b.height % numWorkers
+	defer b.mu.Unlock()
+	if b.run != run || !b.processing || b.stop {
+		return
+	}
+	b.stop = true
+	b.stopReason = stubs.StopTimeout
+	b.cancelTurn()
+}
 
-	var wg sync.WaitGroup
-	wg.Add(numWorkers)
-	newWorld := make([][]uint8, b.height)
-	for i := 0; i < b.height; i++ {
-		newWorld[i] = make([]uint8, b.width)
+// untilNextTurn returns how long the run must wait for its next turn to keep
+// to its target speed. The caller must hold b.mu.
+func (b *Broker) untilNextTurn() time.Duration {
+	if b.speed <= 0 {
+		return 0
 	}
+	return time.Until(b.lastTurnAt.Add(time.Duration(float64(time.Second) / b.speed)))
+}
 
-	for i := 0; i < numWorkers; i++ {
-		startY := i * rowsPerWorker
-		endY := startY + rowsPerWorker
-		if i == numWorkers-1 {
-			endY += remainder
+// turnSignal returns a channel that is closed when the next turn completes or
+// a run starts or ends.
+func (b *Broker) turnSignal() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.turnDone == nil {
+		b.turnDone = make(chan struct{})
+	}
+	return b.turnDone
+}
+
+// notifyTurn wakes everyone waiting on turnSignal. The caller must hold b.mu.
+func (b *Broker) notifyTurn() {
+	if b.turnDone != nil {
+		close(b.turnDone)
+		b.turnDone = nil
+	}
+}
+
+// chooseGrid picks how many block rows and columns to cut the world into. It
+// uses as many workers as the world allows, then prefers the layout whose
+// halos (roughly rows*width + cols*height cells) are smallest.
+func chooseGrid(width, height, workers int) (rows, cols int) {
+	rows, cols = 1, 1
+	for r := 1; r <= workers && r <= height; r++ {
+		c := workers / r
+		if c > width {
+			c = width
+		}
+		used, bestUsed := r*c, rows*cols
+		if used > bestUsed || (used == bestUsed && r*width+c*height < rows*width+cols*height) {
+			rows, cols = r, c
 		}
-		workerWorld := make([][]uint8, endY-startY+2) // Include ghost rows
+	}
+	return rows, cols
+}
+
+// tileRect appends the tiles of mask covering the given tile rows and columns to rect.
+func (b *Broker) tileRect(rect, mask []bool, firstTileX, lastTileX, firstTileY, lastTileY int) []bool {
+	for ty := firstTileY; ty <= lastTileY; ty++ {
+		rect = append(rect, mask[ty*b.tilesX+firstTileX:ty*b.tilesX+lastTileX+1]...)
+	}
+	return rect
+}
+
+// distributeWork runs one turn. Requests, responses and both world buffers are
+// reused from the previous turn, and each worker connection keeps its own frame
+// buffers, so a turn in steady state allocates almost nothing.
+//
+// If a worker fails, or the turn is not done within b.timeout, the workers that
+// did not answer are left out and errTurnFailed is returned without changing the
+// world. Closing b.cancel abandons the turn with errTurnCancelled.
+func (b *Broker) distributeWork() error {
+	b.mu.Lock()
+	if len(b.live) == 0 {
+		b.mu.Unlock()
+		return errNoWorkers
+	}
+	// Divide world into blocks
+	gridRows, gridCols := chooseGrid(b.width, b.height, len(b.live))
+	numBlocks := gridRows * gridCols
+	if len(b.blocks) != numBlocks {
+		b.blocks = make([]workerBlock, numBlocks)
+		b.done = make(chan *rpc.Call, numBlocks)
+	}
+	cancel := b.cancel
+	for i := range b.changed {
+		b.changed[i] = false
+	}
+
+	for i := 0; i < numBlocks; i++ {
+		startY := i / gridCols * b.height / gridRows
+		endY := (i/gridCols + 1) * b.height / gridRows
+		startX := i % gridCols * b.width / gridCols
+		endX := (i%gridCols + 1) * b.width / gridCols
+
+		// Include ghost rows and columns, wrapping around the edges
+		request := &b.blocks[i].request
+		slice := request.WorldSlice[:0]
 		for y := startY - 1; y <= endY; y++ {
-			row := make([]uint8, b.width)
-			copy(row, b.world[(y+b.height)%b.height])
-			workerWorld[y-startY+1] = row
+			row := b.world[(y+b.height)%b.height*b.width:]
+			slice = append(slice, row[(startX-1+b.width)%b.width])
+			slice = append(slice, row[startX:endX]...)
+			slice = append(slice, row[endX%b.width])
 		}
 
-		request := stubs.WorkerRequest{
+		firstTileX, lastTileX := startX/tileSize, (endX-1)/tileSize
+		firstTileY, lastTileY := startY/tileSize, (endY-1)/tileSize
+		*request = stubs.WorkerRequest{
+			StartX:      startX,
+			EndX:        endX,
 			StartY:      startY,
 			EndY:        endY,
-			WorldSlice:  workerWorld,
+			WorldSlice:  slice,
 			ImageWidth:  b.width,
 			ImageHeight: b.height,
+			Threads:     b.threads,
+			TileSize:    tileSize,
+			Dirty:       b.tileRect(request.Dirty[:0], b.dirty, firstTileX, lastTileX, firstTileY, lastTileY),
+			Turn:        b.turn,
+			Seed:        b.seed,
+			Probability: b.probability,
 		}
 
-		worker := b.workers[i]
-		go func(worker *rpc.Client, request stubs.WorkerRequest, index int) {
-			defer wg.Done()
-			response := new(stubs.WorkerResponse)
-			err := worker.Call(stubs.CalculateNextState, request, response)
-			if err != nil {
-				log.Printf("Error calling worker %d: %v", index, err)
-				return
-			}
-			// Copy the results back into newWorld
-			for y := request.StartY; y < request.EndY; y++ {
-				copy(newWorld[y], response.WorldSlice[y-request.StartY])
-			}
-		}(worker, request, i)
+		response := &b.blocks[i].response
+		response.WorldSlice = response.WorldSlice[:0]
+		response.Changed = response.Changed[:0]
+		b.blocks[i].worker = b.live[i]
+		b.blocks[i].call = b.workers[b.live[i]].Go(stubs.CalculateNextState, request, response, b.done)
 	}
+	b.inTurn = true
 	b.mu.Unlock()
 
-	wg.Wait()
+	var timeout <-chan time.Time
+	if b.timeout > 0 {
+		timer := time.NewTimer(b.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	// Only this goroutine touches next and changed until the swap below.
+	failed := false
+	for n := 0; n < numBlocks; n++ {
+		var call *rpc.Call
+		select {
+		case call = <-b.done:
+		case <-timeout:
+			b.abandonTurn(fmt.Errorf("no answer within %v", b.timeout))
+			return errTurnFailed
+		case <-cancel:
+			b.abandonTurn(nil)
+			return errTurnCancelled
+		}
+		block := b.blockOf(call)
+		block.call = nil
+		request := &block.request
+		if call.Error != nil {
+			b.mu.Lock()
+			b.workerFault(block.worker, fmt.Errorf("block (%d, %d): %v", request.StartX, request.StartY, call.Error))
+			b.mu.Unlock()
+			failed = true
+			continue
+		}
+		response := call.Reply.(*stubs.WorkerResponse)
+		// Copy the results back into next
+		blockWidth := request.EndX - request.StartX
+		for y := request.StartY; y < request.EndY; y++ {
+			copy(b.next[y*b.width+request.StartX:y*b.width+request.EndX], response.WorldSlice[(y-request.StartY)*blockWidth:])
+		}
+		// Neighbouring blocks may share a tile, so merge rather than overwrite.
+		firstTileX := request.StartX / tileSize
+		rectWidth := (request.EndX-1)/tileSize - firstTileX + 1
+		firstTileY := request.StartY / tileSize
+		for j, c := range response.Changed {
+			if c {
+				b.changed[(firstTileY+j/rectWidth)*b.tilesX+firstTileX+j%rectWidth] = true
+			}
+		}
+	}
+	if failed {
+		b.mu.Lock()
+		b.endTurn()
+		b.mu.Unlock()
+		return errTurnFailed
+	}
+
 	b.mu.Lock()
-	b.world = newWorld
+	b.world, b.next = b.next, b.world
+	b.turn++
+	for i, changed := range b.changed {
+		if changed {
+			b.tileTurns[i] = b.turn
+		}
+	}
+	b.expandTiles(b.dirty, b.changed)
+	b.endTurn()
+	b.notifyTurn()
+	b.events.Add(stubs.RunEvent{Kind: stubs.EventTurn, Run: b.run, CompletedTurns: b.turn})
+	b.transfer.LastTurnRawBytes, b.transfer.LastTurnWireBytes = 0, 0
+	for _, stats := range b.workerStats {
+		if stats != nil {
+			raw, wire := stats.Take()
+			b.transfer.LastTurnRawBytes += raw
+			b.transfer.LastTurnWireBytes += wire
+		}
+	}
+	b.transfer.TotalRawBytes += b.transfer.LastTurnRawBytes
+	b.transfer.TotalWireBytes += b.transfer.LastTurnWireBytes
 	b.mu.Unlock()
 
 	return nil
 }
 
-func (b *Broker) waitForProcessingToFinish() {
-	// Wait for processing to finish
+// blockOf returns the block whose pending call is call.
+func (b *Broker) blockOf(call *rpc.Call) *workerBlock {
+	for i := range b.blocks {
+		if b.blocks[i].call == call {
+			return &b.blocks[i]
+		}
+	}
+	panic("broker: reply to an unknown call")
+}
+
+// abandonTurn gives up on the calls still pending in this turn, reporting their
+// workers as failed with err unless it is nil. The calls may still complete into
+// their blocks and done channel, so both are dropped and made afresh next turn.
+func (b *Broker) abandonTurn(err error) {
 	b.mu.Lock()
-	for b.processing {
+	defer b.mu.Unlock()
+	if err != nil {
+		for i := range b.blocks {
+			if b.blocks[i].call != nil {
+				b.workerFault(b.blocks[i].worker, err)
+			}
+		}
+	}
+	b.blocks = nil
+	b.done = nil
+	b.endTurn()
+}
+
+// endTurn applies the edits that waited for the turn in flight. The caller must
+// hold b.mu.
+func (b *Broker) endTurn() {
+	b.inTurn = false
+	for _, e := range b.pending {
+		e.done <- b.applyEdit(e.apply, e.res)
+	}
+	b.pending = nil
+}
+
+// edit applies an edit between turns, waiting for the turn in flight if there
+// is one.
+func (b *Broker) edit(apply func(set func(x, y int, alive bool)) error, res *stubs.EditResponse) error {
+	b.mu.Lock()
+	if b.world == nil {
 		b.mu.Unlock()
-		time.Sleep(100 * time.Millisecond)
-		b.mu.Lock()
+		return conflict(errors.New("there is no world to edit yet"))
+	}
+	if !b.inTurn {
+		defer b.mu.Unlock()
+		return b.applyEdit(apply, res)
 	}
+	done := make(chan error, 1)
+	b.pending = append(b.pending, pendingEdit{apply: apply, res: res, done: done})
 	b.mu.Unlock()
+	return <-done
+}
+
+// applyEdit runs apply on the world, marks the tiles around the cells it flips
+// dirty and tells subscribers about the flips. The caller must hold b.mu.
+func (b *Broker) applyEdit(apply func(set func(x, y int, alive bool)) error, res *stubs.EditResponse) error {
+	var flipped []util.Cell
+	edited := make([]bool, len(b.dirty))
+	err := apply(func(x, y int, alive bool) {
+		cell := uint8(0)
+		if alive {
+			cell = 255
+		}
+		if i := y*b.width + x; b.world[i] != cell {
+			b.world[i] = cell
+			flipped = append(flipped, util.Cell{X: x, Y: y})
+			edited[y/tileSize*b.tilesX+x/tileSize] = true
+		}
+	})
+	if err != nil {
+		return err
+	}
+	res.Turn, res.Flipped = b.turn, len(flipped)
+	if len(flipped) == 0 {
+		return nil
+	}
+	b.edits++
+	b.until.Forget(b.world)
+	for tile, e := range edited {
+		if e {
+			b.tileEdits[tile] = b.edits
+			b.markAround(b.dirty, tile)
+		}
+	}
+	b.notifyTurn()
+	b.events.Add(stubs.RunEvent{Kind: stubs.EventCellsFlipped, Run: b.run, CompletedTurns: b.turn, Cells: flipped})
+	return nil
+}
+
+// SetCells makes the listed cells alive, or dead if req.Dead is set.
+func (b *Broker) SetCells(req *stubs.SetCellsRequest, res *stubs.EditResponse) error {
+	if _, err := b.authorize(req.Token, "SetCells"); err != nil {
+		return err
+	}
+	return b.edit(func(set func(x, y int, alive bool)) error {
+		for _, cell := range req.Cells {
+			if cell.X < 0 || cell.X >= b.width || cell.Y < 0 || cell.Y >= b.height {
+				return badRequest(fmt.Errorf("cell (%d, %d) is outside the %dx%d world", cell.X, cell.Y, b.width, b.height))
+			}
+		}
+		for _, cell := range req.Cells {
+			set(cell.X, cell.Y, !req.Dead)
+		}
+		return nil
+	}, res)
+}
+
+// ClearRegion kills every cell in a rectangle, clipped to the world.
+func (b *Broker) ClearRegion(req *stubs.ClearRegionRequest, res *stubs.EditResponse) error {
+	if _, err := b.authorize(req.Token, "ClearRegion"); err != nil {
+		return err
+	}
+	if req.Width < 0 || req.Height < 0 {
+		return badRequest(fmt.Errorf("region of %dx%d cells has a negative size", req.Width, req.Height))
+	}
+	return b.edit(func(set func(x, y int, alive bool)) error {
+		x0, y0 := clamp(req.X, 0, b.width), clamp(req.Y, 0, b.height)
+		x1, y1 := clamp(req.X+req.Width, x0, b.width), clamp(req.Y+req.Height, y0, b.height)
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				set(x, y, false)
+			}
+		}
+		return nil
+	}, res)
+}
+
+// PastePattern copies a pattern onto the world, wrapping around its edges.
+func (b *Broker) PastePattern(req *stubs.PastePatternRequest, res *stubs.EditResponse) error {
+	if _, err := b.authorize(req.Token, "PastePattern"); err != nil {
+		return err
+	}
+	if req.Width < 0 || req.Height < 0 || len(req.Cells) != req.Width*req.Height {
+		return badRequest(fmt.Errorf("a %dx%d pattern needs %d cells, not %d", req.Width, req.Height, req.Width*req.Height, len(req.Cells)))
+	}
+	return b.edit(func(set func(x, y int, alive bool)) error {
+		if req.Width > b.width || req.Height > b.height {
+			return badRequest(fmt.Errorf("a %dx%d pattern does not fit in the %dx%d world", req.Width, req.Height, b.width, b.height))
+		}
+		// Go's % keeps the sign of the dividend, so bring X and Y into the world first.
+		x0, y0 := (req.X%b.width+b.width)%b.width, (req.Y%b.height+b.height)%b.height
+		for y := 0; y < req.Height; y++ {
+			for x, cell := range req.Cells[y*req.Width : (y+1)*req.Width] {
+				if cell != 0 || !req.Merge {
+					set((x0+x)%b.width, (y0+y)%b.height, cell != 0)
+				}
+			}
+		}
+		return nil
+	}, res)
+}
+
+// waitForProcessingToFinish waits until the goroutine of the last run exits.
+func (b *Broker) waitForProcessingToFinish() {
+	b.mu.Lock()
+	done := b.simDone
+	b.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// worldRows copies the current generation into a fresh row-per-slice world,
+// since the flat buffers are overwritten by later turns.
+func (b *Broker) worldRows() [][]uint8 {
+	world := make([][]uint8, b.height)
+	for y := range world {
+		world[y] = make([]uint8, b.width)
+		copy(world[y], b.world[y*b.width:(y+1)*b.width])
+	}
+	return world
 }
 
 func (b *Broker) GetWorld(req *stubs.GetWorldRequest, res *stubs.GetWorldResponse) error {
+	if _, err := b.authorize(req.Token, "GetWorld"); err != nil {
+		return err
+	}
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	res.World = b.world
 	res.CompletedTurns = b.turn
 	res.Processing = b.processing
-	return nil
+	res.ImageWidth = b.width
+	res.ImageHeight = b.height
+	b.transfer.ControllerRawBytes += int64(len(b.world))
+	if req.Encoding == stubs.EncodingRaw || stubs.ChooseEncoding([]string{req.Encoding}, b.encodings) != req.Encoding {
+		res.World = b.worldRows()
+		b.transfer.ControllerWireBytes += int64(len(b.world))
+		return nil
+	}
+	var c stubs.Compressor
+	var err error
+	res.Encoding = req.Encoding
+	res.Cells, err = c.Encode(nil, req.Encoding, b.world)
+	b.transfer.ControllerWireBytes += int64(len(res.Cells))
+	return err
+}
+
+// GetRegion returns part of the world, shrunk into density tiles when
+// req.Scale is above 1, so that viewers of a large world only fetch what they
+// show. The broker holds the whole world between turns, so no worker is asked.
+func (b *Broker) GetRegion(req *stubs.GetRegionRequest, res *stubs.GetRegionResponse) error {
+	if _, err := b.authorize(req.Token, "GetRegion"); err != nil {
+		return err
+	}
+	if req.Width < 0 || req.Height < 0 {
+		return badRequest(fmt.Errorf("region of %dx%d cells has a negative size", req.Width, req.Height))
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cells, err := b.region(req, res)
+	if err != nil {
+		return err
+	}
+	res.Encoding = stubs.ChooseEncoding([]string{req.Encoding}, b.encodings)
+	if res.Scale > 1 && res.Encoding == stubs.EncodingRLE {
+		res.Encoding = stubs.EncodingRaw
+	}
+	var c stubs.Compressor
+	res.Cells, err = c.Encode(nil, res.Encoding, cells)
+	b.transfer.ControllerRawBytes += int64(len(cells))
+	b.transfer.ControllerWireBytes += int64(len(res.Cells))
+	return err
+}
+
+// region clips the rectangle of req to the world, fills in everything in res
+// but the cells and returns them unencoded. b.mu must be held.
+func (b *Broker) region(req *stubs.GetRegionRequest, res *stubs.GetRegionResponse) ([]uint8, error) {
+	x0, y0 := clamp(req.X, 0, b.width), clamp(req.Y, 0, b.height)
+	x1, y1 := clamp(req.X+req.Width, x0, b.width), clamp(req.Y+req.Height, y0, b.height)
+	scale := req.Scale
+	if scale < 1 {
+		scale = 1
+	}
+	*res = stubs.GetRegionResponse{
+		CompletedTurns: b.turn,
+		ImageWidth:     b.width,
+		ImageHeight:    b.height,
+		X:              x0,
+		Y:              y0,
+		Width:          x1 - x0,
+		Height:         y1 - y0,
+		Scale:          scale,
+	}
+	w, h := (x1-x0+scale-1)/scale, (y1-y0+scale-1)/scale
+	if w*h > stubs.MaxCells {
+		return nil, badRequest(fmt.Errorf("region of %dx%d cells at scale %d is too large, try a larger scale", x1-x0, y1-y0, scale))
+	}
+	cells := make([]uint8, w*h)
+	if scale == 1 {
+		for y := y0; y < y1; y++ {
+			copy(cells[(y-y0)*w:], b.world[y*b.width+x0:y*b.width+x1])
+		}
+		return cells, nil
+	}
+	alive := make([]int, w*h)
+	for y := y0; y < y1; y++ {
+		tiles := alive[(y-y0)/scale*w:]
+		for x, cell := range b.world[y*b.width+x0 : y*b.width+x1] {
+			if cell != 0 {
+				tiles[x/scale]++
+			}
+		}
+	}
+	for i, n := range alive {
+		// Tiles on the right and bottom edges may be cut short.
+		tw := clamp(x1-x0-i%w*scale, 1, scale)
+		th := clamp(y1-y0-i/w*scale, 1, scale)
+		cells[i] = uint8(n * 255 / (tw * th))
+	}
+	return cells, nil
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// GetWorldDelta returns the tiles that changed after req.SinceTurn, or the
+// whole world if the client's copy is from another run.
+func (b *Broker) GetWorldDelta(req *stubs.GetWorldDeltaRequest, res *stubs.GetWorldDeltaResponse) error {
+	if _, err := b.authorize(req.Token, "GetWorldDelta"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	*res = stubs.GetWorldDeltaResponse{
+		Run:            b.run,
+		CompletedTurns: b.turn,
+		Edits:          b.edits,
+		Processing:     b.processing,
+		ImageWidth:     b.width,
+		ImageHeight:    b.height,
+		TileSize:       tileSize,
+		Encoding:       stubs.ChooseEncoding([]string{req.Encoding}, b.encodings),
+	}
+	var cells []uint8
+	if req.Run != b.run || req.SinceTurn < 0 || req.SinceTurn > b.turn || req.SinceEdits > b.edits {
+		res.Full = true
+		cells = b.world
+	} else {
+		for tile, turn := range b.tileTurns {
+			if turn > req.SinceTurn || b.tileEdits[tile] > req.SinceEdits {
+				res.Tiles = append(res.Tiles, tile)
+				cells = stubs.AppendTile(cells, b.world, b.width, b.height, tileSize, tile)
+			}
+		}
+	}
+	var c stubs.Compressor
+	var err error
+	res.Cells, err = c.Encode(nil, res.Encoding, cells)
+	b.transfer.ControllerRawBytes += int64(len(b.world))
+	b.transfer.ControllerWireBytes += int64(len(res.Cells))
+	return err
 }
 
 func (b *Broker) Pause(req *stubs.PauseRequest, res *stubs.PauseResponse) error {
+	if _, err := b.authorize(req.Token, "Pause"); err != nil {
+		return err
+	}
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	if !b.processing || b.paused {
+	// Pausing while stepping gives up the rest of the steps.
+	if !b.processing || (b.paused && b.steps == 0) {
 		return nil
 	}
 	b.paused = true
+	b.steps = 0
 	res.Turn = b.turn
+	b.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: b.run, CompletedTurns: b.turn, State: stubs.StatePaused})
 	return nil
 }
 
 func (b *Broker) Resume(req *stubs.ResumeRequest, res *stubs.ResumeResponse) error {
+	if _, err := b.authorize(req.Token, "Resume"); err != nil {
+		return err
+	}
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	if !b.processing || !b.paused {
 		return nil
 	}
 	b.paused = false
+	if b.steps == 0 {
+		b.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: b.run, CompletedTurns: b.turn, State: stubs.StateExecuting})
+	}
+	b.steps = 0
+	return nil
+}
+
+// SetSpeed sets the target speed of the current run.
+func (b *Broker) SetSpeed(req *stubs.SetSpeedRequest, res *stubs.SetSpeedResponse) error {
+	if _, err := b.authorize(req.Token, "SetSpeed"); err != nil {
+		return err
+	}
+	if req.TurnsPerSecond != 0 && !(req.TurnsPerSecond >= stubs.MinSpeed && req.TurnsPerSecond <= math.MaxFloat64) {
+		return badRequest(fmt.Errorf("speed must be 0 for unlimited or at least %v turns per second, not %v", stubs.MinSpeed, req.TurnsPerSecond))
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.speed = req.TurnsPerSecond
+	b.events.Add(stubs.RunEvent{Kind: stubs.EventSpeed, Run: b.run, CompletedTurns: b.turn, TurnsPerSecond: b.speed})
+	return nil
+}
+
+// Step runs req.Turns more turns of a paused run, or fewer if the run ends
+// first, and pauses it again. Steps asked for while stepping add up.
+func (b *Broker) Step(req *stubs.StepRequest, res *stubs.StepResponse) error {
+	if _, err := b.authorize(req.Token, "Step"); err != nil {
+		return err
+	}
+	if req.Turns < 1 {
+		return badRequest(fmt.Errorf("cannot step %d turns", req.Turns))
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.processing || !b.paused {
+		return conflict(errors.New("only a paused run can be stepped"))
+	}
+	if b.steps == 0 {
+		b.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: b.run, CompletedTurns: b.turn, State: stubs.StateExecuting})
+	}
+	b.steps += req.Turns
+	res.Turn = b.turn + b.steps
+	if res.Turn > b.totalTurns {
+		res.Turn = b.totalTurns
+	}
 	return nil
 }
 
 func (b *Broker) Shutdown(req *stubs.ShutdownRequest, res *stubs.ShutdownResponse) error {
+	if _, err := b.authorize(req.Token, "Shutdown"); err != nil {
+		return err
+	}
 	b.mu.Lock()
 	b.shutdown = true
 	b.stop = true
+	b.cancelTurn()
 	b.processing = false
 	b.paused = false
 	b.mu.Unlock()
@@ -217,45 +1163,497 @@ func (b *Broker) Shutdown(req *stubs.ShutdownRequest, res *stubs.ShutdownRespons
 }
 
 func (b *Broker) GetAliveCells(req *stubs.AliveCellsCountRequest, res *stubs.AliveCellsCountResponse) error {
+	if _, err := b.authorize(req.Token, "GetAliveCells"); err != nil {
+		return err
+	}
 	b.mu.Lock()
+	res.CellsCount = b.aliveCount()
+	res.CompletedTurns = b.turn
+	b.mu.Unlock()
+	return nil
+}
+
+// aliveCount counts the alive cells of the current generation. The caller must
+// hold b.mu.
+func (b *Broker) aliveCount() int {
 	count := 0
-	for y := 0; y < b.height; y++ {
-		for x := 0; x < b.width; x++ {
-			if b.world[y][x] == 255 {
-				count++
-			}
+	for _, cell := range b.world {
+		if cell == 255 {
+			count++
 		}
 	}
-	res.CellsCount = count
-	res.CompletedTurns = b.turn
-	b.mu.Unlock()
+	return count
+}
+
+// Subscribe waits for the events after req.After, in the order they happened.
+func (b *Broker) Subscribe(req *stubs.SubscribeRequest, res *stubs.SubscribeResponse) error {
+	if _, err := b.authorize(req.Token, "Subscribe"); err != nil {
+		return err
+	}
+	b.events.Wait(req, res)
 	return nil
 }
 
 func (b *Broker) StopProcessing(req *stubs.StopRequest, res *stubs.StopResponse) error {
+	if _, err := b.authorize(req.Token, "StopProcessing"); err != nil {
+		return err
+	}
 	b.mu.Lock()
 	b.stop = true
+	b.cancelTurn()
 	b.processing = false
+	b.paused = false
 	b.mu.Unlock()
 	return nil
 }
 
+// brokerStatus is the JSON body of the HTTP status endpoints.
+type brokerStatus struct {
+	CompletedTurns int
+	TotalTurns     int
+	ImageWidth     int
+	ImageHeight    int
+	Processing     bool
+	Paused         bool
+	TargetSpeed    float64 `json:",omitempty"`
+	StopReason     string  `json:",omitempty"`
+	Transfer       transferStats
+	FailedWorkers  []string
+	Error          string `json:",omitempty"`
+}
+
+func (b *Broker) status() brokerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := brokerStatus{
+		CompletedTurns: b.turn,
+		TotalTurns:     b.totalTurns,
+		ImageWidth:     b.width,
+		ImageHeight:    b.height,
+		Processing:     b.processing,
+		Paused:         b.paused,
+		TargetSpeed:    b.speed,
+		StopReason:     b.stopReason,
+		Transfer:       b.transfer,
+	}
+	for i, addr := range b.workerAddrs {
+		if !b.isLive(i) {
+			status.FailedWorkers = append(status.FailedWorkers, addr)
+		}
+	}
+	if b.runErr != nil {
+		status.Error = b.runErr.Error()
+	}
+	if b.turn > 0 {
+		status.Transfer.SavedBytesPerTurn = (b.transfer.TotalRawBytes - b.transfer.TotalWireBytes) / int64(b.turn)
+	}
+	return status
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+// statusError is an error the HTTP API answers with code rather than 500. Over
+// net/rpc and gRPC only its message travels.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e statusError) Error() string {
+	return e.err.Error()
+}
+
+// badRequest marks err as a mistake in the request, answered with 400.
+func badRequest(err error) error {
+	return statusError{http.StatusBadRequest, err}
+}
+
+// conflict marks err as a request the state of the run does not allow,
+// answered with 409.
+func conflict(err error) error {
+	return statusError{http.StatusConflict, err}
+}
+
+// httpStatus returns the status to answer err with.
+func httpStatus(err error) int {
+	var s statusError
+	if errors.As(err, &s) {
+		return s.code
+	}
+	return http.StatusInternalServerError
+}
+
+// httpToken returns the token of an HTTP request, sent either as a bearer token
+// or, for browsers, as the token query parameter.
+func httpToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.URL.Query().Get("token")
+}
+
+// authorizeHTTP checks that r may call method and answers 401 or 403 if not.
+func (b *Broker) authorizeHTTP(w http.ResponseWriter, r *http.Request, method string) bool {
+	role, err := b.authorize(httpToken(r), method)
+	if err == nil {
+		return true
+	}
+	if role == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, err)
+	} else {
+		writeError(w, http.StatusForbidden, err)
+	}
+	return false
+}
+
+// rpcHandler exposes the net/rpc style method name of b as a JSON endpoint
+// answering httpMethod. A POST body, if any, must be application/json and is
+// decoded into the request, whose Token is then taken from the HTTP request.
+func (b *Broker) rpcHandler(name, httpMethod string) http.HandlerFunc {
+	method := reflect.ValueOf(b).MethodByName(name)
+	argsType := method.Type().In(0).Elem()
+	replyType := method.Type().In(1).Elem()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != httpMethod {
+			w.Header().Set("Allow", httpMethod)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("use %s", httpMethod))
+			return
+		}
+		if !b.authorizeHTTP(w, r, name) {
+			return
+		}
+		args, reply := reflect.New(argsType), reflect.New(replyType)
+		if r.Method == http.MethodPost && r.ContentLength != 0 {
+			if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+				writeError(w, http.StatusUnsupportedMediaType, errors.New("send the request as application/json"))
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(args.Interface()); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		args.Elem().FieldByName("Token").SetString(httpToken(r))
+		out := method.Call([]reflect.Value{args, reply})
+		if err, _ := out[0].Interface().(error); err != nil {
+			writeError(w, httpStatus(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, reply.Interface())
+	}
+}
+
+// readPgm parses a binary PGM image into a world of at most stubs.MaxCells cells.
+func readPgm(data []byte) ([][]uint8, int, int, error) {
+	var magic string
+	var width, height, maxval int
+	header := bytes.NewReader(data)
+	if _, err := fmt.Fscan(header, &magic, &width, &height, &maxval); err != nil || magic != "P5" || header.Len() == 0 {
+		return nil, 0, 0, errors.New("not a binary pgm file")
+	}
+	if width <= 0 || height <= 0 || width > stubs.MaxCells/height {
+		return nil, 0, 0, fmt.Errorf("a %dx%d world is empty or larger than %d cells", width, height, stubs.MaxCells)
+	}
+	// A single whitespace character separates the header from the pixels.
+	pixels := data[len(data)-header.Len()+1:]
+	if maxval != 255 || len(pixels) < width*height {
+		return nil, 0, 0, fmt.Errorf("expected %dx%d pixels with maxval 255", width, height)
+	}
+	world := make([][]uint8, height)
+	for y := range world {
+		world[y] = pixels[y*width : (y+1)*width]
+	}
+	return world, width, height, nil
+}
+
+// handleProcess starts a run from either a JSON EngineRequest or a PGM body,
+// in which case turns, threads, seed and probability come from the query.
+func (b *Broker) handleProcess(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+		return
+	}
+	if !b.authorizeHTTP(w, r, "Process") {
+		return
+	}
+	req := new(stubs.EngineRequest)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	} else {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		req.World, req.ImageWidth, req.ImageHeight, err = readPgm(data)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		query := r.URL.Query()
+		req.Turns, _ = strconv.Atoi(query.Get("turns"))
+		req.Threads, _ = strconv.Atoi(query.Get("threads"))
+		req.Seed, _ = strconv.ParseUint(query.Get("seed"), 10, 64)
+		req.Probability, _ = strconv.ParseFloat(query.Get("probability"), 64)
+	}
+	req.Token = httpToken(r)
+	res := new(stubs.EngineResponse)
+	if err := b.Process(req, res); err != nil {
+		writeError(w, httpStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// snapshotImage copies the current generation into a grey image.
+func (b *Broker) snapshotImage() (*image.Gray, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	img := image.NewGray(image.Rect(0, 0, b.width, b.height))
+	copy(img.Pix, b.world)
+	return img, b.turn
+}
+
+// writePgm writes img as a binary PGM.
+func writePgm(w io.Writer, img *image.Gray) error {
+	if _, err := fmt.Fprintf(w, "P5\n%d %d\n255\n", img.Rect.Dx(), img.Rect.Dy()); err != nil {
+		return err
+	}
+	_, err := w.Write(img.Pix)
+	return err
+}
+
+// handleImage downloads the current world as a PGM or PNG image.
+func (b *Broker) handleImage(format string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !b.authorizeHTTP(w, r, "GetWorld") {
+			return
+		}
+		img, turn := b.snapshotImage()
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%vx%vx%v.%v\"", img.Rect.Dx(), img.Rect.Dy(), turn, format))
+		if format == "png" {
+			w.Header().Set("Content-Type", "image/png")
+			_ = png.Encode(w, img)
+			return
+		}
+		w.Header().Set("Content-Type", "image/x-portable-graymap")
+		_ = writePgm(w, img)
+	}
+}
+
+// handleRegionImage serves GetRegion as a PNG, taking x, y, w, h and scale from
+// the query. w and h default to the rest of the world.
+func (b *Broker) handleRegionImage(w http.ResponseWriter, r *http.Request) {
+	if !b.authorizeHTTP(w, r, "GetRegion") {
+		return
+	}
+	query := r.URL.Query()
+	param := func(name string, def int) (int, bool) {
+		if query.Get(name) == "" {
+			return def, true
+		}
+		v, err := strconv.Atoi(query.Get(name))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("bad %s: %v", name, err), http.StatusBadRequest)
+			return 0, false
+		}
+		return v, true
+	}
+	req := new(stubs.GetRegionRequest)
+	var ok bool
+	if req.X, ok = param("x", 0); !ok {
+		return
+	}
+	if req.Y, ok = param("y", 0); !ok {
+		return
+	}
+	if req.Width, ok = param("w", math.MaxInt32); !ok {
+		return
+	}
+	if req.Height, ok = param("h", math.MaxInt32); !ok {
+		return
+	}
+	if req.Scale, ok = param("scale", 1); !ok {
+		return
+	}
+	if req.Width < 0 || req.Height < 0 {
+		http.Error(w, "w and h must not be negative", http.StatusBadRequest)
+		return
+	}
+	res := new(stubs.GetRegionResponse)
+	b.mu.Lock()
+	cells, err := b.region(req, res)
+	b.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(cells) == 0 {
+		http.Error(w, "no cells in the region", http.StatusNotFound)
+		return
+	}
+	img := image.NewGray(image.Rect(0, 0, (res.Width+res.Scale-1)/res.Scale, (res.Height+res.Scale-1)/res.Scale))
+	img.Pix = cells
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("X-Completed-Turns", strconv.Itoa(res.CompletedTurns))
+	_ = png.Encode(w, img)
+}
+
+// handleStatusStream sends the broker status as a server-sent event every
+// interval (default 1s) until the client goes away.
+func (b *Broker) handleStatusStream(w http.ResponseWriter, r *http.Request) {
+	if !b.authorizeHTTP(w, r, "GetAliveCells") {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming unsupported"))
+		return
+	}
+	interval, err := time.ParseDuration(r.URL.Query().Get("interval"))
+	if err != nil || interval <= 0 {
+		interval = time.Second
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		data, _ := json.Marshal(b.status())
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// httpHandler returns the HTTP/JSON control API. Every endpoint maps onto the
+// same Broker methods as the RPC services, so all transports share one state
+// and the same tokens. Status needs the same role as GetAliveCells.
+func (b *Broker) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/process", b.handleProcess)
+	mux.HandleFunc("/world", b.rpcHandler("GetWorld", http.MethodGet))
+	mux.HandleFunc("/world/delta", b.rpcHandler("GetWorldDelta", http.MethodPost))
+	mux.HandleFunc("/alive", b.rpcHandler("GetAliveCells", http.MethodGet))
+	mux.HandleFunc("/events", b.rpcHandler("Subscribe", http.MethodPost))
+	mux.HandleFunc("/region", b.rpcHandler("GetRegion", http.MethodPost))
+	mux.HandleFunc("/region.png", b.handleRegionImage)
+	mux.HandleFunc("/cells", b.rpcHandler("SetCells", http.MethodPost))
+	mux.HandleFunc("/clear", b.rpcHandler("ClearRegion", http.MethodPost))
+	mux.HandleFunc("/paste", b.rpcHandler("PastePattern", http.MethodPost))
+	mux.HandleFunc("/pause", b.rpcHandler("Pause", http.MethodPost))
+	mux.HandleFunc("/step", b.rpcHandler("Step", http.MethodPost))
+	mux.HandleFunc("/speed", b.rpcHandler("SetSpeed", http.MethodPost))
+	mux.HandleFunc("/resume", b.rpcHandler("Resume", http.MethodPost))
+	mux.HandleFunc("/stop", b.rpcHandler("StopProcessing", http.MethodPost))
+	mux.HandleFunc("/shutdown", b.rpcHandler("Shutdown", http.MethodPost))
+	mux.HandleFunc("/world.pgm", b.handleImage("pgm"))
+	mux.HandleFunc("/world.png", b.handleImage("png"))
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if b.authorizeHTTP(w, r, "GetAliveCells") {
+			writeJSON(w, http.StatusOK, b.status())
+		}
+	})
+	mux.HandleFunc("/status/stream", b.handleStatusStream)
+	mux.HandleFunc("/", serveViewerPage)
+	mux.Handle("/ws", websocket.Server{Handler: b.serveViewer, Handshake: checkViewerOrigin})
+	return mux
+}
+
 func main() {
-	workerAddrs := []string{
-		"52.91.7.201:8031",
-		"107.22.85.161:8032",
-		"34.201.147.230:8033",
-		// Add more worker addresses as needed
+	pWorkers := flag.String("workers", "52.91.7.201:8031,107.22.85.161:8032,34.201.147.230:8033", "Comma-separated worker addresses")
+	pWorkerGRPC := flag.Bool("workergrpc", false, "Talk to workers over gRPC instead of net/rpc")
+	pGRPC := flag.String("grpc", "8040", "Port to serve gRPC on, empty to disable")
+	pHTTP := flag.String("http", "8050", "Port to serve the HTTP/JSON control API on, empty to disable")
+	pEncodings := flag.String("encodings", strings.Join(stubs.Encodings, ","), "Comma-separated cell encodings to offer workers and controllers, preferred first, empty for raw only")
+	pOutDir := flag.String("outdir", "out", "Directory the viewer's save command writes worlds to")
+	pMaxCells := flag.Int("maxcells", 0, "Largest world to accept, in cells; 0 for as large as the workers accept")
+	var tlsFiles stubs.TLSFiles
+	flag.StringVar(&tlsFiles.Cert, "tlscert", "", "PEM certificate to serve and dial workers with over TLS, empty for plain TCP")
+	flag.StringVar(&tlsFiles.Key, "tlskey", "", "PEM key of the TLS certificate")
+	flag.StringVar(&tlsFiles.CA, "tlsca", "", "PEM CA certificate that controllers and workers must present certificates from")
+	pTimeout := flag.Duration("timeout", stubs.DefaultTimeout, "How long to wait for a worker to connect or finish a turn before leaving it out, 0 for ever")
+	pTokens := flag.String("tokens", "", "File of \"<role> <token>\" lines, role observer or controller; empty to let anyone do anything")
+	flag.Parse()
+
+	serverTLS, err := tlsFiles.ServerConfig()
+	if err != nil {
+		log.Fatal("Error loading TLS certificates:", err)
+	}
+	clientTLS, err := tlsFiles.ClientConfig()
+	if err != nil {
+		log.Fatal("Error loading TLS certificates:", err)
 	}
 
 	broker := new(Broker)
-	err := broker.connectToWorkers(workerAddrs)
+	if *pEncodings != "" {
+		broker.encodings = strings.Split(*pEncodings, ",")
+	}
+	broker.maxCells = *pMaxCells
+	broker.outDir = *pOutDir
+	broker.timeout = *pTimeout
+	if *pTokens != "" {
+		broker.tokens, err = stubs.LoadTokens(*pTokens)
+		if err != nil {
+			log.Fatal("Error loading tokens:", err)
+		}
+	}
+	err = broker.connectToWorkers(strings.Split(*pWorkers, ","), *pWorkerGRPC, clientTLS)
 	if err != nil {
 		log.Fatal("Failed to connect to workers:", err)
 	}
 
+	if *pGRPC != "" {
+		var opts []grpc.ServerOption
+		if serverTLS != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(serverTLS)))
+		}
+		grpcServer := stubs.NewGRPCServer(opts...)
+		stubs.RegisterBroker(grpcServer, broker)
+		grpcListener, err := net.Listen("tcp", ":"+*pGRPC)
+		if err != nil {
+			log.Fatal("Error starting broker:", err)
+		}
+		log.Println("Broker serving gRPC on port", *pGRPC)
+		go grpcServer.Serve(grpcListener)
+	}
+
+	// Controllers speak net/rpc straight over TCP on 8030, with no HTTP
+	// handshake, so the HTTP API cannot share that listener without sniffing
+	// the first bytes of every connection. It gets a port of its own instead,
+	// which also lets it be firewalled or turned off apart from the RPC port.
+	if *pHTTP != "" {
+		log.Println("Broker serving HTTP on port", *pHTTP)
+		httpServer := &http.Server{Addr: ":" + *pHTTP, Handler: broker.httpHandler(), TLSConfig: serverTLS}
+		go func() {
+			if serverTLS != nil {
+				log.Fatal(httpServer.ListenAndServeTLS("", ""))
+			}
+			log.Fatal(httpServer.ListenAndServe())
+		}()
+	}
+
 	rpc.Register(broker)
-	listener, err := net.Listen("tcp", ":8030") // Broker listens on port 8030
+	listener, err := stubs.Listen(":8030", serverTLS) // Broker listens on port 8030
 	if err != nil {
 		log.Fatal("Error starting broker:", err)
 	}
@@ -0,0 +1,1304 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+	"uk.ac.bris.cs/gameoflife/stubs"
+	"uk.ac.bris.cs/gameoflife/util"
+	"uk.ac.bris.cs/gameoflife/worker"
+)
+
+// echoWorker answers CalculateNextState calls on conn by returning every block
+// unchanged. It reuses its own buffers so that a benchmark only measures the broker.
+func echoWorker(conn net.Conn) {
+	r := stubs.NewFrameReader(conn)
+	w := stubs.NewFrameWriter(conn)
+	var req stubs.WorkerRequest
+	var res stubs.WorkerResponse
+	for {
+		method, seq, _, err := r.ReadHeader()
+		if err != nil {
+			return
+		}
+		if method == stubs.WorkerHello {
+			hello := stubs.HelloResponse{Version: stubs.ProtocolVersion, Rules: stubs.Rules}
+			if r.ReadBody(new(stubs.HelloRequest)) != nil || w.WriteFrame(method, seq, "", &hello) != nil {
+				return
+			}
+			continue
+		}
+		if r.ReadBody(&req) != nil {
+			return
+		}
+		blockWidth, stride := req.EndX-req.StartX, req.EndX-req.StartX+2
+		res.WorldSlice = res.WorldSlice[:0]
+		for y := 1; y <= req.EndY-req.StartY; y++ {
+			res.WorldSlice = append(res.WorldSlice, req.WorldSlice[y*stride+1:y*stride+1+blockWidth]...)
+		}
+		res.Changed = append(res.Changed[:0], req.Dirty...)
+		if w.WriteFrame(method, seq, "", &res) != nil {
+			return
+		}
+	}
+}
+
+// newEchoBroker returns a broker driving the given number of echo workers over
+// in-memory connections, loaded with the 512x512 image.
+func newEchoBroker(tb testing.TB, workers int) *Broker {
+	b := new(Broker)
+	for i := 0; i < workers; i++ {
+		client, server := net.Pipe()
+		go echoWorker(server)
+		b.workers = append(b.workers, rpc.NewClientWithCodec(stubs.NewWorkerClientCodec(client)))
+		b.workerAddrs = append(b.workerAddrs, fmt.Sprint("echo worker ", i))
+		b.live = append(b.live, i)
+	}
+
+	data, err := os.ReadFile("../images/512x512.pgm")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	image := []byte(strings.Fields(string(data))[4])
+	world := make([][]uint8, 512)
+	for y := range world {
+		world[y] = image[y*512 : (y+1)*512]
+	}
+	err = b.Process(&stubs.EngineRequest{World: world, ImageWidth: 512, ImageHeight: 512, Threads: 1}, new(stubs.EngineResponse))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	b.waitForProcessingToFinish()
+	return b
+}
+
+// newWorkerBroker returns a broker driving the given number of connections to
+// a real worker over in-memory pipes, with the framed codecs used in production.
+func newWorkerBroker(tb testing.TB, workers int) *Broker {
+	golWorker := new(worker.GolWorker)
+	server := rpc.NewServer()
+	if err := server.RegisterName("GolWorker", golWorker); err != nil {
+		tb.Fatal(err)
+	}
+	b := new(Broker)
+	for i := 0; i < workers; i++ {
+		client, conn := net.Pipe()
+		go server.ServeCodec(worker.NewServerCodec(conn, golWorker))
+		b.workers = append(b.workers, rpc.NewClientWithCodec(stubs.NewWorkerClientCodec(client)))
+		b.workerAddrs = append(b.workerAddrs, fmt.Sprint("worker ", i))
+		b.live = append(b.live, i)
+	}
+	tb.Cleanup(func() {
+		for _, w := range b.workers {
+			_ = w.Close()
+		}
+	})
+	return b
+}
+
+// loadWorld starts a run of no turns on world, so that the test can then
+// drive turns itself with distributeWork.
+func loadWorld(tb testing.TB, b *Broker, world [][]uint8) {
+	err := b.Process(&stubs.EngineRequest{World: world, ImageWidth: len(world[0]), ImageHeight: len(world), Threads: 2}, new(stubs.EngineResponse))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	b.waitForProcessingToFinish()
+}
+
+// readImage loads a pgm image, such as one of check/images.
+func readImage(tb testing.TB, path string) [][]uint8 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	world, _, _, err := readPgm(data)
+	if err != nil {
+		tb.Fatalf("%s: %v", path, err)
+	}
+	return world
+}
+
+// lifeStep computes the next generation of world cell by cell, as a reference
+// for the broker and its workers.
+func lifeStep(world [][]uint8) [][]uint8 {
+	height, width := len(world), len(world[0])
+	next := make([][]uint8, height)
+	for y := range next {
+		next[y] = make([]uint8, width)
+		for x := range next[y] {
+			neighbours := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if (dx != 0 || dy != 0) && world[(y+dy+height)%height][(x+dx+width)%width] == 255 {
+						neighbours++
+					}
+				}
+			}
+			if neighbours == 3 || neighbours == 2 && world[y][x] == 255 {
+				next[y][x] = 255
+			}
+		}
+	}
+	return next
+}
+
+// sameWorld fails the test at the first cell in which got differs from want.
+func sameWorld(tb testing.TB, got, want [][]uint8, what string) {
+	tb.Helper()
+	for y := range want {
+		for x := range want[y] {
+			if got[y][x] != want[y][x] {
+				tb.Fatalf("%s: cell (%d, %d) is %d, expected %d", what, x, y, got[y][x], want[y][x])
+			}
+		}
+	}
+}
+
+// TestHTTP starts a run by posting a PGM, pauses it and checks that the world
+// downloaded as a PGM matches the one returned by the JSON endpoint.
+func TestHTTP(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	srv := httptest.NewServer(b.httpHandler())
+	defer srv.Close()
+
+	data, err := os.ReadFile("../images/64x64.pgm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(srv.URL+"/process?turns=1000000", "image/x-portable-graymap", bytes.NewReader(data))
+	if err != nil || res.StatusCode != http.StatusOK {
+		t.Fatalf("POST /process: %v %v", res.Status, err)
+	}
+	if res, _ := http.Get(srv.URL + "/pause"); res.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /pause: expected %v, got %v", http.StatusMethodNotAllowed, res.Status)
+	}
+	if _, err := http.Post(srv.URL+"/pause", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	defer http.Post(srv.URL+"/stop", "", nil)
+
+	var status brokerStatus
+	res, _ = http.Get(srv.URL + "/status")
+	if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Paused || status.ImageWidth != 64 || status.ImageHeight != 64 {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	var world struct{ World [][]uint8 }
+	res, _ = http.Get(srv.URL + "/world")
+	if err := json.NewDecoder(res.Body).Decode(&world); err != nil {
+		t.Fatal(err)
+	}
+	res, _ = http.Get(srv.URL + "/world.pgm")
+	pgm, _ := io.ReadAll(res.Body)
+	rows, width, height, err := readPgm(pgm)
+	if err != nil || width != 64 || height != 64 {
+		t.Fatalf("GET /world.pgm: %vx%v %v", width, height, err)
+	}
+	for y := range rows {
+		if !bytes.Equal(rows[y], world.World[y]) {
+			t.Fatalf("row %v differs between /world and /world.pgm", y)
+		}
+	}
+
+	res, err = http.Post(srv.URL+"/step", "text/plain", strings.NewReader(`{"Turns": 1}`))
+	if err != nil || res.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("POST /step as text/plain: expected %v, got %v %v", http.StatusUnsupportedMediaType, res.Status, err)
+	}
+	for _, test := range []struct {
+		path, body string
+		code       int
+	}{
+		{"/step", `{"Turns": 0}`, http.StatusBadRequest},
+		{"/speed", `{"TurnsPerSecond": -1}`, http.StatusBadRequest},
+		{"/process", `{"ImageWidth": 0, "ImageHeight": 0}`, http.StatusBadRequest},
+		{"/process", `{"ImageWidth": 2, "ImageHeight": 1, "World": [[0, 0], [0, 0]]}`, http.StatusBadRequest},
+		{"/resume", ``, http.StatusOK},
+		{"/step", `{"Turns": 1}`, http.StatusConflict},
+	} {
+		res, err := http.Post(srv.URL+test.path, "application/json", strings.NewReader(test.body))
+		if err != nil || res.StatusCode != test.code {
+			t.Errorf("POST %s %s: expected %v, got %v %v", test.path, test.body, test.code, res.Status, err)
+		}
+	}
+	for _, header := range []string{"P5 0 64 255\n", "P5 64 -1 255\n", "P5 100000 100000 255\n"} {
+		if _, _, _, err := readPgm([]byte(header + "x")); err == nil {
+			t.Errorf("expected %q to be refused", header)
+		}
+	}
+}
+
+// TestProcessShape checks that a raw world with missing, extra or short rows is
+// refused before it replaces the current one.
+func TestProcessShape(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	world := append([]uint8(nil), b.world...)
+	rows := func(n, width int) [][]uint8 {
+		r := make([][]uint8, n)
+		for y := range r {
+			r[y] = make([]uint8, width)
+		}
+		return r
+	}
+	short := rows(4, 4)
+	short[2] = short[2][:3]
+	for name, test := range map[string][][]uint8{
+		"missing rows": rows(3, 4),
+		"extra rows":   rows(5, 4),
+		"short row":    short,
+		"long rows":    rows(4, 5),
+		"no world":     nil,
+	} {
+		err := b.Process(&stubs.EngineRequest{World: test, ImageWidth: 4, ImageHeight: 4, Turns: 1}, new(stubs.EngineResponse))
+		if err == nil {
+			t.Errorf("%s: expected the world to be refused", name)
+		}
+	}
+	if !bytes.Equal(b.world, world) || b.width != 512 {
+		t.Error("a refused world replaced the current one")
+	}
+}
+
+// TestViewerOrigin checks which pages may open a viewer socket.
+func TestViewerOrigin(t *testing.T) {
+	for _, test := range []struct {
+		host, origin string
+		allowed      bool
+	}{
+		{"gol.example:8080", "http://gol.example:8080", true},
+		{"gol.example:8080", "http://localhost:3000", true},
+		{"gol.example:8080", "http://127.0.0.1:3000", true},
+		{"gol.example:8080", "http://[::1]:3000", true},
+		{"gol.example:8080", "http://gol.example:9090", false},
+		{"gol.example:8080", "https://example.com", false},
+		{"gol.example:8080", "", false},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		r.Host = test.host
+		if test.origin != "" {
+			r.Header.Set("Origin", test.origin)
+		}
+		err := checkViewerOrigin(&websocket.Config{Version: websocket.ProtocolVersionHybi13}, r)
+		if (err == nil) != test.allowed {
+			t.Errorf("origin %q on %s: expected allowed %v, got %v", test.origin, test.host, test.allowed, err)
+		}
+	}
+}
+
+// TestViewer checks that a viewer first receives the whole world and that its
+// pause command shows up in a later frame.
+func TestViewer(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	srv := httptest.NewServer(b.httpHandler())
+	defer srv.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	_ = ws.SetDeadline(time.Now().Add(5 * time.Second))
+	if other, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", "", "http://example.com"); err == nil {
+		other.Close()
+		t.Error("expected a viewer from another site to be refused")
+	}
+
+	var frame viewerFrame
+	if err := websocket.JSON.Receive(ws, &frame); err != nil {
+		t.Fatal(err)
+	}
+	alive := new(stubs.AliveCellsCountResponse)
+	_ = b.GetAliveCells(new(stubs.AliveCellsCountRequest), alive)
+	if frame.Type != "world" || frame.Width != 512 || len(frame.Cells) != alive.CellsCount {
+		t.Fatalf("expected a 512x512 world frame with %v cells, got %v %vx%v with %v cells",
+			alive.CellsCount, frame.Type, frame.Width, frame.Height, len(frame.Cells))
+	}
+
+	err = b.Process(&stubs.EngineRequest{World: b.worldRows(), ImageWidth: 512, ImageHeight: 512, Turns: 1000000}, new(stubs.EngineResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.StopProcessing(new(stubs.StopRequest), new(stubs.StopResponse))
+	if err := websocket.JSON.Send(ws, viewerCommand{Command: "pause"}); err != nil {
+		t.Fatal(err)
+	}
+	for !frame.Paused {
+		if err := websocket.JSON.Receive(ws, &frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestViewerDiffs drives turns and an edit on real workers and checks that a
+// copy of the world kept up to date with the viewer's frames matches the
+// broker's after each, then saves the world with the viewer's save command.
+func TestViewerDiffs(t *testing.T) {
+	b := newWorkerBroker(t, 2)
+	b.outDir = filepath.Join(t.TempDir(), "saved")
+	loadWorld(t, b, readImage(t, "../images/64x64.pgm"))
+	srv := httptest.NewServer(b.httpHandler())
+	defer srv.Close()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	_ = ws.SetDeadline(time.Now().Add(10 * time.Second))
+
+	mirror := make([]uint8, 64*64)
+	var frame viewerFrame
+	catchUp := func(what string) {
+		for !bytes.Equal(mirror, b.world) {
+			frame = viewerFrame{}
+			if err := websocket.JSON.Receive(ws, &frame); err != nil {
+				t.Fatalf("%s: %v", what, err)
+			}
+			for _, i := range frame.Cells {
+				mirror[i] ^= 255
+			}
+		}
+	}
+	catchUp("world")
+	for turn := 1; turn <= 10; turn++ {
+		if err := b.distributeWork(); err != nil {
+			t.Fatal(err)
+		}
+		catchUp(fmt.Sprintf("turn %d", turn))
+		if frame.Type != "diff" {
+			t.Errorf("turn %d: expected a diff, got a %s frame", turn, frame.Type)
+		}
+	}
+	if err := b.SetCells(&stubs.SetCellsRequest{Cells: []util.Cell{{X: 40, Y: 40}}}, new(stubs.EditResponse)); err != nil {
+		t.Fatal(err)
+	}
+	catchUp("edit")
+
+	if err := websocket.JSON.Send(ws, viewerCommand{Command: "save"}); err != nil {
+		t.Fatal(err)
+	}
+	for frame.Type != "saved" {
+		if err := websocket.JSON.Receive(ws, &frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := filepath.Join(b.outDir, "64x64x10.pgm"); frame.File != want {
+		t.Errorf("expected the world saved as %s, got %s", want, frame.File)
+	}
+	sameWorld(t, readImage(t, frame.File), b.worldRows(), "saved world")
+
+	b.outDir = frame.File
+	if _, err := b.saveWorld(); err == nil {
+		t.Error("expected saving into a file rather than a directory to fail")
+	}
+}
+
+// TestWorldDelta checks that a copy of the world kept up to date with deltas
+// matches the broker's, and that a copy from another run gets a whole world.
+func TestWorldDelta(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	delta := func(run, since int) *stubs.GetWorldDeltaResponse {
+		res := new(stubs.GetWorldDeltaResponse)
+		err := b.GetWorldDelta(&stubs.GetWorldDeltaRequest{Run: run, SinceTurn: since, Encoding: stubs.EncodingRLE}, res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	res := delta(0, 0)
+	if !res.Full {
+		t.Fatal("expected a whole world for a copy from another run")
+	}
+	mirror, err := stubs.ApplyWorldDelta(nil, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fake a turn that flips one cell in the tile holding (100, 200).
+	b.mu.Lock()
+	b.world[200*512+100] ^= 255
+	b.turn++
+	b.tileTurns[200/tileSize*b.tilesX+100/tileSize] = b.turn
+	b.mu.Unlock()
+
+	res = delta(res.Run, res.CompletedTurns)
+	if res.Full || len(res.Tiles) != 1 {
+		t.Fatalf("expected one tile, got full %v and tiles %v", res.Full, res.Tiles)
+	}
+	if mirror, err = stubs.ApplyWorldDelta(mirror, res); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(mirror, b.world) {
+		t.Error("copy differs from the broker's world after applying the delta")
+	}
+	if res = delta(res.Run, res.CompletedTurns); res.Full || len(res.Tiles) != 0 {
+		t.Errorf("expected an empty delta, got full %v and tiles %v", res.Full, res.Tiles)
+	}
+}
+
+// TestGetRegion checks that a region is clipped to the world and that density
+// tiles count the alive cells under them, including cut-short edge tiles.
+func TestGetRegion(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	b.mu.Lock()
+	for i := range b.world {
+		b.world[i] = 0
+	}
+	// Fill the top left 4x4 cells and one cell in the last column.
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			b.world[y*512+x] = 255
+		}
+	}
+	b.world[2*512+511] = 255
+	b.mu.Unlock()
+
+	res := new(stubs.GetRegionResponse)
+	if err := b.GetRegion(&stubs.GetRegionRequest{X: 2, Y: 2, Width: 4, Height: 4, Encoding: stubs.EncodingRLE}, res); err != nil {
+		t.Fatal(err)
+	}
+	cells, err := stubs.DecodeWorld(res.Encoding, res.Cells, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cells[0][0] != 255 || cells[1][1] != 255 || cells[2][0] != 0 || cells[0][2] != 0 {
+		t.Errorf("unexpected region %v", cells)
+	}
+
+	req := &stubs.GetRegionRequest{X: 0, Y: 0, Width: 600, Height: 8, Scale: 8, Encoding: stubs.EncodingRLE}
+	if err := b.GetRegion(req, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Width != 512 || res.Height != 8 || res.Encoding == stubs.EncodingRLE {
+		t.Fatalf("expected a clipped 512x8 region not in RLE, got %dx%d in %q", res.Width, res.Height, res.Encoding)
+	}
+	tiles, err := stubs.DecodeWorld(res.Encoding, res.Cells, 64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tiles[0][0] != 255*16/64 || tiles[0][1] != 0 || tiles[0][63] != 255/64 {
+		t.Errorf("unexpected densities %v", tiles[0])
+	}
+
+	req = &stubs.GetRegionRequest{X: 508, Y: 0, Width: 4, Height: 8, Scale: 3}
+	if err := b.GetRegion(req, res); err != nil {
+		t.Fatal(err)
+	}
+	// The 4x8 region is two tiles wide, the second one cell wide, and three tiles
+	// high, so the cell at (511, 2) is a third of its tile.
+	if len(res.Cells) != 6 || res.Cells[1] != 255/3 || res.Cells[0] != 0 {
+		t.Errorf("unexpected edge tiles %v", res.Cells)
+	}
+}
+
+// TestHello checks that the broker turns away a controller speaking another
+// protocol version and tells a current one what it supports.
+func TestHello(t *testing.T) {
+	b := new(Broker)
+	b.encodings = stubs.Encodings
+	server := rpc.NewServer()
+	if err := server.Register(b); err != nil {
+		t.Fatal(err)
+	}
+	client, conn := net.Pipe()
+	go server.ServeConn(conn)
+	c := rpc.NewClient(client)
+	defer c.Close()
+
+	old := &stubs.HelloRequest{Version: stubs.ProtocolVersion - 1, Rules: stubs.Rules}
+	if _, err := stubs.SayHello(c.Call, stubs.Hello, "broker", old); err == nil || !strings.Contains(err.Error(), "protocol version") {
+		t.Errorf("expected a protocol version error, got %v", err)
+	}
+	current := &stubs.HelloRequest{Version: stubs.ProtocolVersion, Rules: stubs.Rules, Encodings: []string{stubs.EncodingFlate}}
+	res, err := stubs.SayHello(c.Call, stubs.Hello, "broker", current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Encoding != stubs.EncodingFlate || !stubs.HasFeature(res.Features, stubs.FeatureWorldDelta) {
+		t.Errorf("unexpected hello %+v", res)
+	}
+}
+
+// TestTokens checks that an observer token can watch but not control a run, over
+// RPC and HTTP, and that calls without a token are turned away.
+func TestTokens(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	b.tokens = stubs.Tokens{"watch": stubs.RoleObserver, "drive": stubs.RoleController}
+
+	if err := b.GetWorld(&stubs.GetWorldRequest{Token: "watch"}, new(stubs.GetWorldResponse)); err != nil {
+		t.Errorf("observer GetWorld: %v", err)
+	}
+	err := b.Shutdown(&stubs.ShutdownRequest{Token: "watch"}, new(stubs.ShutdownResponse))
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("observer Shutdown: expected permission denied, got %v", err)
+	}
+	err = b.GetAliveCells(new(stubs.AliveCellsCountRequest), new(stubs.AliveCellsCountResponse))
+	if err == nil || !strings.Contains(err.Error(), "unauthorized") {
+		t.Errorf("GetAliveCells without a token: expected unauthorized, got %v", err)
+	}
+	if err := b.Pause(&stubs.PauseRequest{Token: "drive"}, new(stubs.PauseResponse)); err != nil {
+		t.Errorf("controller Pause: %v", err)
+	}
+
+	srv := httptest.NewServer(b.httpHandler())
+	defer srv.Close()
+	request := func(method, path, token string, expected int) {
+		req, _ := http.NewRequest(method, srv.URL+path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != expected {
+			t.Errorf("%s %s with token %q: expected %v, got %v", method, path, token, expected, res.Status)
+		}
+	}
+	request(http.MethodGet, "/world", "", http.StatusUnauthorized)
+	request(http.MethodGet, "/world", "watch", http.StatusOK)
+	request(http.MethodGet, "/status?token=watch", "", http.StatusOK)
+	request(http.MethodPost, "/stop", "watch", http.StatusForbidden)
+	request(http.MethodPost, "/stop", "drive", http.StatusOK)
+}
+
+// TestSubscribe checks that a subscriber sees a run start, its turns and its
+// end in order, and nothing from the run before.
+func TestSubscribe(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	res := new(stubs.EngineResponse)
+	if err := b.Process(&stubs.EngineRequest{World: b.worldRows(), ImageWidth: 512, ImageHeight: 512, Turns: 5}, res); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []stubs.RunEvent
+	req := &stubs.SubscribeRequest{Run: res.Run, Wait: 5 * time.Second}
+	for len(events) == 0 || events[len(events)-1].Kind != stubs.EventFinished {
+		sub := new(stubs.SubscribeResponse)
+		if err := b.Subscribe(req, sub); err != nil {
+			t.Fatal(err)
+		}
+		if len(sub.Events) == 0 {
+			t.Fatalf("no events after %v", events)
+		}
+		events = append(events, sub.Events...)
+		req.After = sub.Events[len(sub.Events)-1].Seq
+	}
+
+	first, last := events[0], events[len(events)-1]
+	if first.Kind != stubs.EventState || first.State != stubs.StateExecuting || last.CompletedTurns != 5 {
+		t.Errorf("expected the run to start executing and finish after 5 turns, got %+v", events)
+	}
+	turn := 0
+	for i, e := range events {
+		if e.Run != res.Run || (i > 0 && e.Seq <= events[i-1].Seq) {
+			t.Fatalf("event %+v is out of order or from another run", e)
+		}
+		if e.Kind == stubs.EventTurn {
+			if e.CompletedTurns <= turn {
+				t.Errorf("turn %v reported after turn %v", e.CompletedTurns, turn)
+			}
+			turn = e.CompletedTurns
+		}
+	}
+}
+
+// TestEdits checks that edits wait for the turn in flight, reach GetWorldDelta
+// without a turn completing and are reported as flipped cells.
+func TestEdits(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	res := new(stubs.EditResponse)
+	if err := b.ClearRegion(&stubs.ClearRegionRequest{X: -10, Y: -10, Width: 1000, Height: 1000}, res); err != nil {
+		t.Fatal(err)
+	}
+	delta := new(stubs.GetWorldDeltaResponse)
+	if err := b.GetWorldDelta(&stubs.GetWorldDeltaRequest{Run: 0}, delta); err != nil {
+		t.Fatal(err)
+	}
+	mirror, err := stubs.ApplyWorldDelta(nil, delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pretend a turn is in flight, so that the paste waits for it.
+	b.mu.Lock()
+	b.inTurn = true
+	b.mu.Unlock()
+	pasted := make(chan error)
+	glider := []uint8{0, 255, 0, 0, 0, 255, 255, 255, 255}
+	go func() {
+		pasted <- b.PastePattern(&stubs.PastePatternRequest{X: -1, Y: -1, Width: 3, Height: 3, Cells: glider}, res)
+	}()
+	select {
+	case err := <-pasted:
+		t.Fatalf("paste took effect during a turn: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	b.mu.Lock()
+	b.turn++
+	b.endTurn()
+	b.mu.Unlock()
+	if err := <-pasted; err != nil {
+		t.Fatal(err)
+	}
+	if res.Turn != 1 || res.Flipped != 5 {
+		t.Errorf("expected 5 cells flipped at turn 1, got %+v", res)
+	}
+	// The glider wraps around from (511, 511).
+	for _, i := range []int{511*512 + 0, 0*512 + 1, 1*512 + 511, 1*512 + 0, 1*512 + 1} {
+		if b.world[i] != 255 {
+			t.Errorf("cell %d, %d is dead after the paste", i%512, i/512)
+		}
+	}
+
+	req := &stubs.GetWorldDeltaRequest{Run: delta.Run, SinceTurn: 1, SinceEdits: delta.Edits}
+	if err := b.GetWorldDelta(req, delta); err != nil {
+		t.Fatal(err)
+	}
+	if delta.Full || len(delta.Tiles) != 3 {
+		t.Fatalf("expected three corner tiles, got full %v and tiles %v", delta.Full, delta.Tiles)
+	}
+	if mirror, err = stubs.ApplyWorldDelta(mirror, delta); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(mirror, b.world) {
+		t.Error("copy differs from the broker's world after the edit")
+	}
+
+	if err := b.SetCells(&stubs.SetCellsRequest{Cells: []util.Cell{{X: 0, Y: 1}, {X: 5, Y: 5}}}, res); err != nil || res.Flipped != 1 {
+		t.Errorf("expected one cell flipped, got %+v and %v", res, err)
+	}
+	if err := b.SetCells(&stubs.SetCellsRequest{Cells: []util.Cell{{X: 5, Y: 5}, {X: 512, Y: 0}}, Dead: true}, res); err == nil || b.world[5*512+5] != 255 {
+		t.Error("expected an edit with a cell outside the world to fail without changing it")
+	}
+
+	sub := new(stubs.SubscribeResponse)
+	b.Subscribe(&stubs.SubscribeRequest{}, sub)
+	var flips []stubs.RunEvent
+	for _, e := range sub.Events {
+		if e.Kind == stubs.EventCellsFlipped {
+			flips = append(flips, e)
+		}
+	}
+	if len(flips) != 3 || len(flips[1].Cells) != 5 || len(flips[2].Cells) != 1 || flips[2].Cells[0] != (util.Cell{X: 5, Y: 5}) {
+		t.Errorf("expected flips from the clear, the paste and one cell, got %d events", len(flips))
+	}
+}
+
+// TestStep checks that stepping a paused run completes exactly the turns asked
+// for and reports the run executing and then paused again.
+func TestStep(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	if err := b.Process(&stubs.EngineRequest{World: b.worldRows(), ImageWidth: 512, ImageHeight: 512, Turns: 1000000}, new(stubs.EngineResponse)); err != nil {
+		t.Fatal(err)
+	}
+	defer b.StopProcessing(new(stubs.StopRequest), new(stubs.StopResponse))
+	paused := new(stubs.PauseResponse)
+	if err := b.Pause(new(stubs.PauseRequest), paused); err != nil {
+		t.Fatal(err)
+	}
+	sub := new(stubs.SubscribeResponse)
+	b.Subscribe(&stubs.SubscribeRequest{}, sub)
+	after := sub.Events[len(sub.Events)-1].Seq
+
+	step := new(stubs.StepResponse)
+	if err := b.Step(&stubs.StepRequest{Turns: 3}, step); err != nil {
+		t.Fatal(err)
+	}
+	if step.Turn != paused.Turn+3 {
+		t.Errorf("expected to pause at turn %d, got %d", paused.Turn+3, step.Turn)
+	}
+	var states []stubs.RunEvent
+	for len(states) < 2 {
+		if err := b.Subscribe(&stubs.SubscribeRequest{After: after, Wait: 5 * time.Second}, sub); err != nil || len(sub.Events) == 0 {
+			t.Fatalf("no state events after %+v: %v", states, err)
+		}
+		for _, e := range sub.Events {
+			if e.Kind == stubs.EventState {
+				states = append(states, e)
+			}
+			after = e.Seq
+		}
+	}
+	if states[0].State != stubs.StateExecuting || states[1].State != stubs.StatePaused || states[1].CompletedTurns != step.Turn {
+		t.Errorf("expected executing and then paused at turn %d, got %+v", step.Turn, states)
+	}
+	b.mu.Lock()
+	turn := b.turn
+	b.mu.Unlock()
+	if turn != step.Turn {
+		t.Errorf("run moved on to turn %d after stepping to %d", turn, step.Turn)
+	}
+	if err := b.Resume(new(stubs.ResumeRequest), new(stubs.ResumeResponse)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Step(&stubs.StepRequest{Turns: 1}, step); err == nil {
+		t.Error("expected stepping a running run to fail")
+	}
+}
+
+// TestSetSpeed checks that a run held to a target speed completes no more turns
+// than that rate allows in the time that actually passed, fewer than the same
+// run unthrottled, and that an unusable speed is refused.
+func TestSetSpeed(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	if err := b.Process(&stubs.EngineRequest{World: b.worldRows(), ImageWidth: 512, ImageHeight: 512, Turns: 1000000}, new(stubs.EngineResponse)); err != nil {
+		t.Fatal(err)
+	}
+	defer b.StopProcessing(new(stubs.StopRequest), new(stubs.StopResponse))
+	// count returns the turns completed in about half a second at speed, and
+	// how long it really took.
+	count := func(speed float64) (int, time.Duration) {
+		if err := b.SetSpeed(&stubs.SetSpeedRequest{TurnsPerSecond: speed}, new(stubs.SetSpeedResponse)); err != nil {
+			t.Fatal(err)
+		}
+		// Let a turn already in flight finish before counting.
+		time.Sleep(100 * time.Millisecond)
+		b.mu.Lock()
+		start, started := b.turn, time.Now()
+		b.mu.Unlock()
+		time.Sleep(500 * time.Millisecond)
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.turn - start, time.Since(started)
+	}
+
+	throttled, elapsed := count(20)
+	if b.status().TargetSpeed != 20 {
+		t.Errorf("status reports a target speed of %v", b.status().TargetSpeed)
+	}
+	// Turns are at least 1/20s apart, so elapsed allows one more than 20 a second.
+	if limit := int(elapsed.Seconds()*20) + 1; throttled < 1 || throttled > limit {
+		t.Errorf("expected 1 to %d turns in %v at 20 turns a second, got %d", limit, elapsed, throttled)
+	}
+	if unthrottled, _ := count(0); unthrottled <= throttled {
+		t.Errorf("expected more than the %d throttled turns without a target speed, got %d", throttled, unthrottled)
+	}
+	for _, speed := range []float64{-1, stubs.MinSpeed / 2, math.Inf(1), math.NaN()} {
+		if err := b.SetSpeed(&stubs.SetSpeedRequest{TurnsPerSecond: speed}, new(stubs.SetSpeedResponse)); err == nil {
+			t.Errorf("expected a speed of %v to be refused", speed)
+		}
+	}
+}
+
+// TestStopConditions checks that a run ends as soon as a stop condition holds
+// and reports why. Echo workers never change the world, so it is a still life.
+func TestStopConditions(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	alive := b.aliveCount()
+	for _, test := range []struct {
+		until  stubs.StopConditions
+		reason string
+		turn   int
+	}{
+		{stubs.StopConditions{Period: 1}, stubs.StopCycle, 1},
+		{stubs.StopConditions{Above: alive - 1}, stubs.StopAbove, 1},
+		{stubs.StopConditions{Below: alive + 1, Period: 1}, stubs.StopBelow, 1},
+		{stubs.StopConditions{Below: alive}, "", 20},
+	} {
+		res := new(stubs.EngineResponse)
+		if err := b.Process(&stubs.EngineRequest{World: b.worldRows(), ImageWidth: 512, ImageHeight: 512, Turns: 20, Until: test.until}, res); err != nil {
+			t.Fatal(err)
+		}
+		b.waitForProcessingToFinish()
+		sub := new(stubs.SubscribeResponse)
+		b.Subscribe(&stubs.SubscribeRequest{Run: res.Run}, sub)
+		last := sub.Events[len(sub.Events)-1]
+		if last.Kind != stubs.EventFinished || last.Reason != test.reason || last.CompletedTurns != test.turn || last.CellsCount != alive {
+			t.Errorf("%+v: expected to finish after %d turns because %q, got %+v", test.until, test.turn, test.reason, last)
+		}
+	}
+	if err := b.Process(&stubs.EngineRequest{World: b.worldRows(), ImageWidth: 512, ImageHeight: 512, Until: stubs.StopConditions{Above: -1}}, new(stubs.EngineResponse)); err == nil {
+		t.Error("expected a negative condition to be refused")
+	}
+}
+
+// TestStopTimeout checks that a run's Timeout stops it on time while it is
+// paused, held back to a low speed or waiting for a worker that never answers,
+// not only once its next turn completes.
+func TestStopTimeout(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+	for _, wait := range []string{"paused", "throttled", "hung worker"} {
+		var b *Broker
+		if wait == "hung worker" {
+			b = newEchoBroker(t, 0)
+			addHungWorker(b)
+		} else {
+			b = newEchoBroker(t, 2)
+		}
+		started := time.Now()
+		res := new(stubs.EngineResponse)
+		req := &stubs.EngineRequest{World: b.worldRows(), ImageWidth: 512, ImageHeight: 512, Turns: 1000000, Until: stubs.StopConditions{Timeout: timeout}}
+		if err := b.Process(req, res); err != nil {
+			t.Fatal(err)
+		}
+		switch wait {
+		case "paused":
+			_ = b.Pause(new(stubs.PauseRequest), new(stubs.PauseResponse))
+		case "throttled":
+			_ = b.SetSpeed(&stubs.SetSpeedRequest{TurnsPerSecond: stubs.MinSpeed}, new(stubs.SetSpeedResponse))
+		}
+		stopped := make(chan struct{})
+		go func() {
+			b.waitForProcessingToFinish()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: the run went on past its timeout", wait)
+		}
+		if elapsed := time.Since(started); elapsed < timeout {
+			t.Errorf("%s: the run stopped after %v, before its timeout", wait, elapsed)
+		}
+		sub := new(stubs.SubscribeResponse)
+		b.Subscribe(&stubs.SubscribeRequest{Run: res.Run}, sub)
+		if last := sub.Events[len(sub.Events)-1]; last.Kind != stubs.EventFinished || last.Reason != stubs.StopTimeout {
+			t.Errorf("%s: expected to finish because of the timeout, got %+v", wait, last)
+		}
+	}
+}
+
+// TestTileSkipping runs real workers on the 16x16, 64x64 and 512x512 images
+// and checks turns 1 and 100 against check/images, so that copying clean tiles
+// through gives the same world as recomputing every cell.
+func TestTileSkipping(t *testing.T) {
+	for _, size := range []int{16, 64, 512} {
+		b := newWorkerBroker(t, 4)
+		loadWorld(t, b, readImage(t, fmt.Sprintf("../images/%dx%d.pgm", size, size)))
+		for turn := 1; turn <= 100; turn++ {
+			if err := b.distributeWork(); err != nil {
+				t.Fatal(err)
+			}
+			if turn == 1 || turn == 100 {
+				want := readImage(t, fmt.Sprintf("../check/images/%dx%dx%d.pgm", size, size, turn))
+				sameWorld(t, b.worldRows(), want, fmt.Sprintf("%dx%d turn %d", size, size, turn))
+			}
+		}
+		if size == 512 {
+			clean := 0
+			for _, dirty := range b.dirty {
+				if !dirty {
+					clean++
+				}
+			}
+			if clean == 0 {
+				t.Error("512x512: expected some tiles to have gone still by turn 100")
+			}
+		}
+	}
+}
+
+// TestTilesWake lets a world of blocks go still, so that every tile is clean,
+// then drops two gliders into it with SetCells. One crosses tile corners into a
+// block straddling four tiles; the other wraps round the corner of the world.
+// Every turn is checked against a full recompute.
+func TestTilesWake(t *testing.T) {
+	const size = 128
+	world := make([][]uint8, size)
+	for y := range world {
+		world[y] = make([]uint8, size)
+	}
+	for _, corner := range []util.Cell{{X: 31, Y: 31}, {X: 100, Y: 60}, {X: 63, Y: 95}} {
+		for _, c := range []util.Cell{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}} {
+			world[corner.Y+c.Y][corner.X+c.X] = 255
+		}
+	}
+	b := newWorkerBroker(t, 3)
+	loadWorld(t, b, world)
+
+	for turn := 1; turn <= 3; turn++ {
+		if err := b.distributeWork(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sameWorld(t, b.worldRows(), world, "still life")
+	for tile, dirty := range b.dirty {
+		if dirty {
+			t.Fatalf("tile %d is still dirty in a still world", tile)
+		}
+	}
+
+	var gliders []util.Cell
+	for _, at := range []util.Cell{{X: 14, Y: 14}, {X: 122, Y: 122}} {
+		for _, c := range []util.Cell{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}} {
+			gliders = append(gliders, util.Cell{X: at.X + c.X, Y: at.Y + c.Y})
+			world[at.Y+c.Y][at.X+c.X] = 255
+		}
+	}
+	if err := b.SetCells(&stubs.SetCellsRequest{Cells: gliders}, new(stubs.EditResponse)); err != nil {
+		t.Fatal(err)
+	}
+	for turn := 1; turn <= 200; turn++ {
+		if err := b.distributeWork(); err != nil {
+			t.Fatal(err)
+		}
+		world = lifeStep(world)
+		sameWorld(t, b.worldRows(), world, fmt.Sprintf("turn %d after waking", turn))
+	}
+}
+
+// TestChooseGrid checks the block layouts picked for square, wide, short,
+// one-cell-thin and prime-sized worlds, with more workers than rows or columns.
+func TestChooseGrid(t *testing.T) {
+	for _, test := range []struct {
+		width, height, workers int
+		rows, cols             int
+	}{
+		{512, 512, 1, 1, 1},
+		{512, 512, 4, 2, 2},
+		{512, 512, 16, 4, 4},
+		{512, 4, 16, 1, 16},
+		{100, 3, 8, 1, 8},
+		{2, 2, 7, 2, 2},
+		{1, 50, 4, 4, 1},
+		{50, 1, 4, 1, 4},
+		{1, 1, 16, 1, 1},
+		{97, 89, 6, 2, 3},
+		{64, 64, 7, 1, 7},
+	} {
+		rows, cols := chooseGrid(test.width, test.height, test.workers)
+		if rows != test.rows || cols != test.cols {
+			t.Errorf("%dx%d with %d workers: expected %dx%d blocks, got %dx%d", test.width, test.height, test.workers, test.rows, test.cols, rows, cols)
+		}
+		if rows > test.height || cols > test.width || rows*cols > test.workers {
+			t.Errorf("%dx%d with %d workers: %dx%d blocks leave a block or worker without work", test.width, test.height, test.workers, rows, cols)
+		}
+	}
+}
+
+// TestBlocks runs 1-16 real workers on wide, short and thin worlds, and on one
+// cut into blocks in both directions so that corner halos are used, checking
+// every turn against a full recompute.
+func TestBlocks(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	corners := false
+	for _, size := range []struct{ width, height int }{{300, 1}, {512, 4}, {97, 3}, {1, 50}, {64, 48}} {
+		start := make([][]uint8, size.height)
+		for y := range start {
+			start[y] = make([]uint8, size.width)
+			for x := range start[y] {
+				if random.Intn(3) == 0 {
+					start[y][x] = 255
+				}
+			}
+		}
+		for workers := 1; workers <= 16; workers++ {
+			rows, cols := chooseGrid(size.width, size.height, workers)
+			corners = corners || rows > 1 && cols > 1
+			b := newWorkerBroker(t, workers)
+			loadWorld(t, b, start)
+			world := start
+			for turn := 1; turn <= 20; turn++ {
+				if err := b.distributeWork(); err != nil {
+					t.Fatal(err)
+				}
+				world = lifeStep(world)
+				sameWorld(t, b.worldRows(), world, fmt.Sprintf("%dx%d with %d workers in %dx%d blocks, turn %d", size.width, size.height, workers, rows, cols, turn))
+			}
+		}
+	}
+	if !corners {
+		t.Error("no world was cut into blocks in both directions")
+	}
+}
+
+// TestStochasticReproducible runs noisy Life through the broker with 1-16 real
+// workers, in the blocks chooseGrid picks and with tile skipping on, and checks
+// that every run ends on the same world as the single worker run. The 64x64
+// image sits in a corner of an empty world, so that most tiles stay clean.
+func TestStochasticReproducible(t *testing.T) {
+	const turns = 100
+	start := make([][]uint8, 256)
+	for y := range start {
+		start[y] = make([]uint8, 256)
+	}
+	for y, row := range readImage(t, "../images/64x64.pgm") {
+		copy(start[y], row)
+	}
+	run := func(workers int) ([][]uint8, int) {
+		b := newWorkerBroker(t, workers)
+		req := &stubs.EngineRequest{World: start, ImageWidth: 256, ImageHeight: 256, Turns: turns, Threads: 2, Seed: 42, Probability: 0.5}
+		if err := b.Process(req, new(stubs.EngineResponse)); err != nil {
+			t.Fatal(err)
+		}
+		b.waitForProcessingToFinish()
+		clean := 0
+		for _, dirty := range b.dirty {
+			if !dirty {
+				clean++
+			}
+		}
+		return b.worldRows(), clean
+	}
+
+	expected, clean := run(1)
+	if clean == 0 {
+		t.Error("expected some tiles to have gone still, so that skipping them is tested")
+	}
+	for workers := 2; workers <= 16; workers++ {
+		rows, cols := chooseGrid(256, 256, workers)
+		world, _ := run(workers)
+		sameWorld(t, world, expected, fmt.Sprintf("%d workers in %dx%d blocks", workers, rows, cols))
+	}
+}
+
+// addHungWorker adds a worker to b that reads every call and never answers. The
+// returned channel is closed once the first call reaches it.
+func addHungWorker(b *Broker) <-chan struct{} {
+	client, server := net.Pipe()
+	called := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1)
+		if _, err := server.Read(buf); err == nil {
+			close(called)
+			_, _ = io.Copy(io.Discard, server)
+		}
+	}()
+	b.workers = append(b.workers, rpc.NewClientWithCodec(stubs.NewWorkerClientCodec(client)))
+	b.workerAddrs = append(b.workerAddrs, "hung worker")
+	b.live = append(b.live, len(b.workers)-1)
+	return called
+}
+
+// TestWorkerTimeout checks that a worker which stops answering is left out once
+// a turn times out, and that the run carries on with the others.
+func TestWorkerTimeout(t *testing.T) {
+	b := newEchoBroker(t, 2)
+	addHungWorker(b)
+	b.timeout = 100 * time.Millisecond
+	before := b.worldRows()
+
+	err := b.Process(&stubs.EngineRequest{World: before, ImageWidth: 512, ImageHeight: 512, Turns: 3}, new(stubs.EngineResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.waitForProcessingToFinish()
+	status := b.status()
+	if status.CompletedTurns != 3 || len(status.FailedWorkers) != 1 || status.FailedWorkers[0] != "hung worker" {
+		t.Fatalf("expected 3 turns without the hung worker, got %+v", status)
+	}
+	for y, row := range b.worldRows() {
+		if !bytes.Equal(row, before[y]) {
+			t.Fatalf("row %v was changed by a turn the hung worker never finished", y)
+		}
+	}
+}
+
+// TestStopCancelsTurn checks that stopping a run abandons a turn stuck on a
+// worker that never answers, even without a timeout.
+func TestStopCancelsTurn(t *testing.T) {
+	b := newEchoBroker(t, 0)
+	called := addHungWorker(b)
+	err := b.Process(&stubs.EngineRequest{World: b.worldRows(), ImageWidth: 512, ImageHeight: 512, Turns: 10}, new(stubs.EngineResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-called
+	if err := b.StopProcessing(new(stubs.StopRequest), new(stubs.StopResponse)); err != nil {
+		t.Fatal(err)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		b.waitForProcessingToFinish()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run did not stop while a turn was in flight")
+	}
+}
+
+// TestTLS runs a turn on a worker over mutual TLS and checks that the broker's
+// listener accepts a controller with a certificate from the CA and turns away
+// one without.
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	if err := stubs.GenerateCerts(dir, []string{"127.0.0.1"}, []string{"broker", "worker", "controller"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	tlsConfigs := func(node string) (server, client *tls.Config) {
+		files := stubs.NodeTLSFiles(dir, node)
+		server, err := files.ServerConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err = files.ClientConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return server, client
+	}
+	workerTLS, _ := tlsConfigs("worker")
+	brokerServerTLS, brokerClientTLS := tlsConfigs("broker")
+	_, controllerTLS := tlsConfigs("controller")
+
+	workerListener, err := stubs.Listen("127.0.0.1:0", workerTLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer workerListener.Close()
+	go func() {
+		for {
+			conn, err := workerListener.Accept()
+			if err != nil {
+				return
+			}
+			go echoWorker(conn)
+		}
+	}()
+
+	b := new(Broker)
+	if err := b.connectToWorkers([]string{workerListener.Addr().String()}, false, brokerClientTLS); err != nil {
+		t.Fatal(err)
+	}
+	world := [][]uint8{{0, 255, 0}, {0, 255, 0}, {0, 255, 0}}
+	if err := b.Process(&stubs.EngineRequest{World: world, ImageWidth: 3, ImageHeight: 3, Turns: 1}, new(stubs.EngineResponse)); err != nil {
+		t.Fatal(err)
+	}
+	b.waitForProcessingToFinish()
+	if b.turn != 1 {
+		t.Errorf("expected 1 turn over TLS, got %v", b.turn)
+	}
+
+	server := rpc.NewServer()
+	if err := server.Register(b); err != nil {
+		t.Fatal(err)
+	}
+	brokerListener, err := stubs.Listen("127.0.0.1:0", brokerServerTLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer brokerListener.Close()
+	go server.Accept(brokerListener)
+	addr := brokerListener.Addr().String()
+
+	conn, err := stubs.Dial(addr, controllerTLS, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := rpc.NewClient(conn)
+	defer c.Close()
+	hello := &stubs.HelloRequest{Version: stubs.ProtocolVersion, Rules: stubs.Rules}
+	if _, err := stubs.SayHello(c.Call, stubs.Hello, "broker", hello); err != nil {
+		t.Fatal(err)
+	}
+
+	anonymous := controllerTLS.Clone()
+	anonymous.Certificates = nil
+	conn, err = stubs.Dial(addr, anonymous, time.Second)
+	if err == nil {
+		c := rpc.NewClient(conn)
+		defer c.Close()
+		_, err = stubs.SayHello(c.Call, stubs.Hello, "broker", hello)
+	}
+	if err == nil {
+		t.Error("expected a controller without a certificate to be turned away")
+	}
+}
+
+// TestWorkerGRPC runs the images through a broker whose workers are real
+// GolWorkers served over gRPC, as with -workergrpc, with the broker itself
+// driven over gRPC, and checks the results against check/images and that the
+// bytes sent to the workers were counted.
+func TestWorkerGRPC(t *testing.T) {
+	var addrs []string
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := stubs.NewGRPCServer()
+		stubs.RegisterWorker(server, new(worker.GolWorker))
+		go server.Serve(listener)
+		defer server.Stop()
+		addrs = append(addrs, listener.Addr().String())
+	}
+	b := &Broker{encodings: stubs.Encodings}
+	if err := b.connectToWorkers(addrs, true, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, w := range b.workers {
+			_ = w.Close()
+		}
+	}()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := stubs.NewGRPCServer()
+	stubs.RegisterBroker(server, b)
+	go server.Serve(listener)
+	defer server.Stop()
+	client, err := stubs.DialGRPC(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for _, size := range []int{16, 64, 512} {
+		world := readImage(t, fmt.Sprintf("../images/%dx%d.pgm", size, size))
+		for _, turns := range []int{1, 100} {
+			req := stubs.EngineRequest{World: world, ImageWidth: size, ImageHeight: size, Turns: turns, Threads: 2}
+			if err := client.Call(stubs.Process, &req, new(stubs.EngineResponse)); err != nil {
+				t.Fatal(err)
+			}
+			b.waitForProcessingToFinish()
+			var res stubs.GetWorldResponse
+			if err := client.Call(stubs.GetWorld, &stubs.GetWorldRequest{}, &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.CompletedTurns != turns {
+				t.Errorf("%dx%d: expected %d turns, got %d", size, size, turns, res.CompletedTurns)
+			}
+			want := readImage(t, fmt.Sprintf("../check/images/%dx%dx%d.pgm", size, size, turns))
+			sameWorld(t, res.World, want, fmt.Sprintf("%dx%d turn %d over gRPC", size, size, turns))
+		}
+	}
+	if raw, wire := b.transfer.TotalRawBytes, b.transfer.TotalWireBytes; raw < 512*512*100 || wire <= 0 || wire >= raw {
+		t.Errorf("expected gzip to shrink at least 100 turns of 512x512 cells, counted %d bytes as %d", raw, wire)
+	}
+
+	err = client.Call(stubs.Step, &stubs.StepRequest{Turns: 1}, new(stubs.StepResponse))
+	if _, ok := err.(rpc.ServerError); !ok {
+		t.Errorf("expected stepping a finished run to fail with a server error, got %v", err)
+	}
+}
+
+// BenchmarkDistributeWork reports the allocations of one broker turn across
+// four workers once the broker's buffers have warmed up.
+func BenchmarkDistributeWork(b *testing.B) {
+	broker := newEchoBroker(b, 4)
+	if err := broker.distributeWork(); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := broker.distributeWork(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
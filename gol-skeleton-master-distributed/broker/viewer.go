@@ -0,0 +1,229 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/net/websocket"
+	"uk.ac.bris.cs/gameoflife/stubs"
+)
+
+// The browser viewer replaces the SDL window when the broker runs headless. The
+// page draws the world on a canvas from frames pushed over a WebSocket and sends
+// pause, save and quit commands back on the same socket. A token in the page's
+// query is passed on to the socket: watching needs an observer token and the
+// commands a controller one.
+
+//go:embed viewer.html
+var viewerPage []byte
+
+// viewerFrameInterval caps how often a viewer is sent a frame. Turns completed
+// in between are folded into the next diff.
+const viewerFrameInterval = time.Second / 60
+
+// viewerPollInterval is how often a viewer is refreshed while no turns are
+// completing, so that pausing and stopping still show up.
+const viewerPollInterval = 250 * time.Millisecond
+
+// viewerFrame is sent to the browser. A "world" frame lists every alive cell
+// and replaces the picture; a "diff" frame lists the cells flipped since the
+// previous frame. Cells are indices y*Width+x.
+type viewerFrame struct {
+	Type       string
+	Turn       int
+	Width      int
+	Height     int
+	Processing bool
+	Paused     bool
+	Cells      []int
+	File       string
+	Error      string
+}
+
+// viewerCommand is sent by the browser. Command is "pause", "save" or "quit".
+type viewerCommand struct {
+	Command string
+}
+
+func serveViewerPage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(viewerPage)
+}
+
+// checkViewerOrigin only lets pages from the broker itself or from this machine
+// open a viewer socket, so that another site open in the browser cannot watch
+// or drive the run.
+func checkViewerOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		return errors.New("null origin")
+	}
+	config.Origin = origin
+	host := origin.Hostname()
+	if origin.Host == r.Host || host == "localhost" || net.ParseIP(host).IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("origin %s is not allowed", origin)
+}
+
+// serveViewer streams the world to one browser until it disconnects.
+func (b *Broker) serveViewer(ws *websocket.Conn) {
+	defer ws.Close()
+	token := httpToken(ws.Request())
+	if _, err := b.authorize(token, "GetWorld"); err != nil {
+		_ = websocket.JSON.Send(ws, viewerFrame{Type: "error", Error: err.Error()})
+		return
+	}
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		b.readViewerCommands(ws, token)
+	}()
+
+	var mirror []uint8
+	var sent viewerFrame
+	run, sinceTurn, sinceEdits := -1, 0, 0
+	for {
+		// Take the signal before reading the state so that no turn is missed.
+		signal := b.turnSignal()
+		b.mu.Lock()
+		frame := viewerFrame{
+			Type:       "diff",
+			Turn:       b.turn,
+			Width:      b.width,
+			Height:     b.height,
+			Processing: b.processing,
+			Paused:     b.paused,
+		}
+		if run != b.run || len(mirror) != len(b.world) {
+			frame.Type = "world"
+			mirror = append(mirror[:0], b.world...)
+			for i, cell := range mirror {
+				if cell == 255 {
+					frame.Cells = append(frame.Cells, i)
+				}
+			}
+		} else {
+			frame.Cells = b.flippedSince(mirror, sinceTurn, sinceEdits)
+		}
+		run, sinceTurn, sinceEdits = b.run, b.turn, b.edits
+		b.mu.Unlock()
+
+		if frame.Type == "world" || len(frame.Cells) > 0 || frame.Turn != sent.Turn ||
+			frame.Processing != sent.Processing || frame.Paused != sent.Paused {
+			if err := websocket.JSON.Send(ws, frame); err != nil {
+				return
+			}
+			sent = frame
+		}
+
+		select {
+		case <-closed:
+			return
+		case <-time.After(viewerFrameInterval):
+		}
+		select {
+		case <-closed:
+			return
+		case <-signal:
+		case <-time.After(viewerPollInterval):
+		}
+	}
+}
+
+// flippedSince lists the cells that differ from mirror, looking only in the
+// tiles that changed after turn sinceTurn or edit sinceEdits as GetWorldDelta
+// does, and brings mirror up to date. b.mu must be held.
+func (b *Broker) flippedSince(mirror []uint8, sinceTurn, sinceEdits int) []int {
+	var cells []int
+	for tile, turn := range b.tileTurns {
+		if turn <= sinceTurn && b.tileEdits[tile] <= sinceEdits {
+			continue
+		}
+		x0, y0 := tile%b.tilesX*tileSize, tile/b.tilesX*tileSize
+		x1, y1 := clamp(x0+tileSize, x0, b.width), clamp(y0+tileSize, y0, b.height)
+		for y := y0; y < y1; y++ {
+			for i := y*b.width + x0; i < y*b.width+x1; i++ {
+				if b.world[i] != mirror[i] {
+					mirror[i] = b.world[i]
+					cells = append(cells, i)
+				}
+			}
+		}
+	}
+	return cells
+}
+
+// readViewerCommands carries out commands from the browser with its token until
+// the socket is closed. The viewer's pause toggles like the controller's 'p' key.
+func (b *Broker) readViewerCommands(ws *websocket.Conn, token string) {
+	for {
+		var command viewerCommand
+		if err := websocket.JSON.Receive(ws, &command); err != nil {
+			return
+		}
+		var err error
+		switch command.Command {
+		case "pause":
+			b.mu.Lock()
+			paused := b.paused
+			b.mu.Unlock()
+			if paused {
+				err = b.Resume(&stubs.ResumeRequest{Token: token}, new(stubs.ResumeResponse))
+			} else {
+				err = b.Pause(&stubs.PauseRequest{Token: token}, new(stubs.PauseResponse))
+			}
+		case "save":
+			if _, err = b.authorize(token, "SaveWorld"); err != nil {
+				break
+			}
+			var file string
+			file, err = b.saveWorld()
+			if err == nil {
+				err = websocket.JSON.Send(ws, viewerFrame{Type: "saved", File: file})
+			}
+		case "quit":
+			err = b.StopProcessing(&stubs.StopRequest{Token: token}, new(stubs.StopResponse))
+		default:
+			err = fmt.Errorf("unknown command %q", command.Command)
+		}
+		if err != nil {
+			log.Println("Viewer command failed:", err)
+			_ = websocket.JSON.Send(ws, viewerFrame{Type: "error", Error: err.Error()})
+		}
+	}
+}
+
+// saveWorld writes the current generation to b.outDir, out by default, named
+// like the controller's own output, and returns the file name.
+func (b *Broker) saveWorld() (string, error) {
+	img, turn := b.snapshotImage()
+	dir := b.outDir
+	if dir == "" {
+		dir = "out"
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	filename := filepath.Join(dir, fmt.Sprintf("%vx%vx%v.pgm", img.Rect.Dx(), img.Rect.Dy(), turn))
+	file, err := os.Create(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return filename, writePgm(file, img)
+}
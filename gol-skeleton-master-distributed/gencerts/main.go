@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+)
+
+// gencerts writes a self-signed CA and certificates for the nodes of a cluster,
+// for use with the -tlscert, -tlskey and -tlsca flags of the controller, the
+// broker and the workers. For example
+//
+//	go run ./gencerts -hosts localhost,127.0.0.1,52.91.7.201
+//	go run ./broker -tlscert certs/broker.pem -tlskey certs/broker-key.pem -tlsca certs/ca.pem
+func main() {
+	pOut := flag.String("out", "certs", "Directory to write the certificates to")
+	pHosts := flag.String("hosts", "localhost,127.0.0.1", "Comma-separated host names and IP addresses the nodes are reached at")
+	pNodes := flag.String("nodes", "broker,worker,controller", "Comma-separated node names to make certificates for")
+	pValid := flag.Duration("valid", 365*24*time.Hour, "How long the certificates are valid for")
+	flag.Parse()
+
+	nodes := strings.Split(*pNodes, ",")
+	err := stubs.GenerateCerts(*pOut, strings.Split(*pHosts, ","), nodes, *pValid)
+	if err != nil {
+		log.Fatal("Error generating certificates:", err)
+	}
+	fmt.Printf("Wrote %s/ca.pem and certificates for %s\n", *pOut, strings.Join(nodes, ", "))
+}
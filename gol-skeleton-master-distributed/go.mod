@@ -1,5 +1,17 @@
 module uk.ac.bris.cs/gameoflife
 
-go 1.17
+go 1.19
 
-require github.com/veandco/go-sdl2 v0.4.38
+require (
+	github.com/veandco/go-sdl2 v0.4.38
+	golang.org/x/net v0.17.0
+	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.33.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
+)
@@ -3,7 +3,7 @@ package gol
 import (
 	"fmt"
 	"log"
-	"net/rpc"
+	"path/filepath"
 	"time"
 
 	"uk.ac.bris.cs/gameoflife/stubs"
@@ -22,7 +22,9 @@ type distributorChannels struct {
 func handleOutput(p Params, c distributorChannels, world [][]uint8, t int) {
 	c.ioCommand <- ioOutput
 	outFilename := fmt.Sprintf("%vx%vx%v", p.ImageWidth, p.ImageHeight, t)
-	c.ioFilename <- outFilename
+	format, err := formatNamed(p.OutputFormat)
+	util.Check(err)
+	c.ioFilename <- outFilename + format.extension
 	for y := 0; y < p.ImageHeight; y++ {
 		for x := 0; x < p.ImageWidth; x++ {
 			c.ioOutput <- world[y][x]
@@ -34,9 +36,14 @@ func handleOutput(p Params, c distributorChannels, world [][]uint8, t int) {
 	c.events <- ImageOutputComplete{
 		CompletedTurns: t,
 		Filename:       outFilename,
+		Path:           filepath.Join(p.outDir(), outFilename+format.extension),
 	}
 }
 
+// speeds are the target speeds the '+' and '-' keys step through, in turns per
+// second. One past the fastest is unlimited.
+var speeds = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
+
 func distributor(p Params, c distributorChannels, keyPresses <-chan rune) {
 	world := make([][]uint8, p.ImageHeight)
 	for i := range world {
@@ -44,6 +51,9 @@ func distributor(p Params, c distributorChannels, keyPresses <-chan rune) {
 	}
 
 	filename := fmt.Sprintf("%vx%v", p.ImageWidth, p.ImageHeight)
+	if p.Pattern != "" {
+		filename = p.Pattern
+	}
 
 	c.ioCommand <- ioInput
 	c.ioFilename <- filename
@@ -60,30 +70,20 @@ func distributor(p Params, c distributorChannels, keyPresses <-chan rune) {
 		}
 	}
 
-	//client, err := rpc.Dial("tcp", "localhost:8030") Connect to AWS instance
-	client, err := rpc.Dial("tcp", "3.84.187.222:8030") // Connect to Broker
+	engine, err := NewEngine(p)
 	if err != nil {
 		log.Fatal("Failed connecting:", err)
 	}
-	defer client.Close()
+	defer engine.Close()
 
-	request := &stubs.EngineRequest{
-		World:       world,
-		ImageWidth:  p.ImageWidth,
-		ImageHeight: p.ImageHeight,
-		Turns:       p.Turns,
-	}
-	response := new(stubs.EngineResponse)
-
-	err = client.Call(stubs.Process, request, response)
+	err = engine.Start(p, world)
 	if err != nil {
 		log.Fatal("Error calling Process:", err)
 	}
-
-	ticker := time.NewTicker(2 * time.Second)
-	done := make(chan bool)
-	processingDone := make(chan bool)
+	done := make(chan bool, 1)
+	finished := make(chan struct{})
 	paused := false
+	speed := len(speeds)
 
 	go func() {
 		for {
@@ -91,71 +91,72 @@ func distributor(p Params, c distributorChannels, keyPresses <-chan rune) {
 			case key := <-keyPresses:
 				switch key {
 				case 's':
-					getWorldRequest := &stubs.GetWorldRequest{}
-					getWorldResponse := new(stubs.GetWorldResponse)
-					err := client.Call(stubs.GetWorld, getWorldRequest, getWorldResponse)
+					worldSnapshot, turn, _, err := engine.Snapshot()
 					if err != nil {
 						log.Println("Error calling GetWorld:", err)
 					} else {
-						worldSnapshot := getWorldResponse.World
-						turn := getWorldResponse.CompletedTurns
 						handleOutput(p, c, worldSnapshot, turn)
 					}
 				case 'q':
 					done <- true
 					return
 				case 'k':
-					shutdownRequest := &stubs.ShutdownRequest{}
-					shutdownResponse := new(stubs.ShutdownResponse)
-					err := client.Call(stubs.Shutdown, shutdownRequest, shutdownResponse)
+					err := engine.Shutdown()
 					if err != nil {
 						log.Println("Error calling Shutdown:", err)
 					}
-					getWorldRequest := &stubs.GetWorldRequest{}
-					getWorldResponse := new(stubs.GetWorldResponse)
-					err = client.Call(stubs.GetWorld, getWorldRequest, getWorldResponse)
+					worldSnapshot, turn, _, err := engine.Snapshot()
 					if err != nil {
 						log.Println("Error calling GetWorld:", err)
 					} else {
-						worldSnapshot := getWorldResponse.World
-						turn := getWorldResponse.CompletedTurns
 						handleOutput(p, c, worldSnapshot, turn)
 					}
 					done <- true
 					return
+				case 'n', 'N':
+					steps := 1
+					if key == 'N' {
+						steps = 100
+					}
+					turn, err := engine.Step(steps)
+					if err != nil {
+						log.Println("Error calling Step:", err)
+					} else {
+						fmt.Printf("Stepping to turn %d\n", turn)
+					}
+				case '+', '-':
+					next := speed + 1
+					if key == '-' {
+						next = speed - 1
+					}
+					if next < 0 || next > len(speeds) {
+						break
+					}
+					target := 0.0
+					if next < len(speeds) {
+						target = speeds[next]
+					}
+					if err := engine.SetSpeed(target); err != nil {
+						log.Println("Error calling SetSpeed:", err)
+					} else {
+						speed = next
+					}
 				case 'p':
 					if !paused {
-						pauseRequest := &stubs.PauseRequest{}
-						pauseResponse := new(stubs.PauseResponse)
-						err := client.Call(stubs.Pause, pauseRequest, pauseResponse)
+						turn, err := engine.Pause()
 						if err != nil {
 							log.Println("Error calling Pause:", err)
 						} else {
-							fmt.Printf("Paused at turn %d\n", pauseResponse.Turn)
+							fmt.Printf("Paused at turn %d\n", turn)
 							paused = true
-							c.events <- StateChange{
-								CompletedTurns: pauseResponse.Turn,
-								NewState:       Paused,
-							}
 						}
 					} else {
-						resumeRequest := &stubs.ResumeRequest{}
-						resumeResponse := new(stubs.ResumeResponse)
-						err := client.Call(stubs.Resume, resumeRequest, resumeResponse)
+						err := engine.Resume()
 						if err != nil {
 							log.Println("Error calling Resume:", err)
 						} else {
 							fmt.Println("Continuing")
 							paused = false
-							getWorldRequest := &stubs.GetWorldRequest{}
-							getWorldResponse := new(stubs.GetWorldResponse)
-							err = client.Call(stubs.GetWorld, getWorldRequest, getWorldResponse)
-							if err == nil {
-								c.events <- StateChange{
-									CompletedTurns: getWorldResponse.CompletedTurns,
-									NewState:       Executing,
-								}
-							}
 						}
 					}
 				}
@@ -163,68 +164,108 @@ func distributor(p Params, c distributorChannels, keyPresses <-chan rune) {
 		}
 	}()
 
+	// The engine pushes the run's events; only this goroutine turns them into
+	// Events, so they reach c.events in order.
+	runEvents := make(chan stubs.RunEvent)
 	go func() {
+		var after uint64
 		for {
-			select {
-			case <-ticker.C:
-				countRequest := &stubs.AliveCellsCountRequest{}
-				countResponse := new(stubs.AliveCellsCountResponse)
-				err := client.Call(stubs.GetAliveCells, countRequest, countResponse)
-				if err != nil {
+			events, err := engine.Subscribe(after, 10*time.Second)
+			if err != nil {
+				select {
+				case <-finished:
+					// Closing the engine at the end of the run cut the poll short.
+					return
+				default:
+				}
+				log.Println("Error calling Subscribe:", err)
+				events = nil
+				select {
+				case <-time.After(time.Second):
+				case <-finished:
 					return
-				} else {
-					if countResponse.CompletedTurns > 0 {
-						aliveReport := AliveCellsCount{
-							CompletedTurns: countResponse.CompletedTurns,
-							CellsCount:     countResponse.CellsCount,
-						}
-						c.events <- aliveReport
-					}
 				}
-			case <-done:
-				ticker.Stop()
-				return
 			}
-		}
-	}()
-
-	go func() {
-		for {
-			time.Sleep(1 * time.Second)
-			getWorldRequest := &stubs.GetWorldRequest{}
-			getWorldResponse := new(stubs.GetWorldResponse)
-			err := client.Call(stubs.GetWorld, getWorldRequest, getWorldResponse)
-			if err != nil {
-				log.Println("Error calling GetWorld:", err)
-			} else {
-				if !getWorldResponse.Processing {
-					processingDone <- true
+			for _, event := range events {
+				after = event.Seq
+				select {
+				case runEvents <- event:
+				case <-finished:
 					return
 				}
 			}
 		}
 	}()
 
-	select {
-	case <-done:
-		stopRequest := &stubs.StopRequest{}
-		stopResponse := new(stubs.StopResponse)
-		err := client.Call(stubs.StopProcessing, stopRequest, stopResponse)
-		if err != nil {
-			log.Println("Error calling StopProcessing:", err)
+	var reason string
+	var period, reported int
+	for running := true; running; {
+		select {
+		case <-done:
+			err := engine.Stop()
+			if err != nil {
+				log.Println("Error calling StopProcessing:", err)
+			}
+			running = false
+		case event := <-runEvents:
+			switch event.Kind {
+			case stubs.EventTurn:
+				// The engine folds turns completed faster than they are read
+				// into the latest, but every turn gets its own TurnComplete.
+				for reported < event.CompletedTurns {
+					reported++
+					c.events <- TurnComplete{CompletedTurns: reported}
+				}
+			case stubs.EventAlive:
+				if event.CompletedTurns > 0 {
+					c.events <- AliveCellsCount{
+						CompletedTurns: event.CompletedTurns,
+						CellsCount:     event.CellsCount,
+					}
+				}
+			case stubs.EventState:
+				state := Executing
+				if event.State == stubs.StatePaused {
+					state = Paused
+				}
+				c.events <- StateChange{
+					CompletedTurns: event.CompletedTurns,
+					NewState:       state,
+				}
+			case stubs.EventSpeed:
+				c.events <- SpeedChange{
+					CompletedTurns: event.CompletedTurns,
+					TurnsPerSecond: event.TurnsPerSecond,
+				}
+			case stubs.EventCellsFlipped:
+				// Edits on the broker happen between turns, so render them
+				// straight away.
+				c.events <- CellsFlipped{
+					CompletedTurns: event.CompletedTurns,
+					Cells:          event.Cells,
+				}
+				c.events <- TurnComplete{CompletedTurns: event.CompletedTurns}
+			case stubs.EventWorkerFailed:
+				log.Printf("Worker %s failed at turn %d: %s", event.Worker, event.CompletedTurns, event.Error)
+			case stubs.EventFinished:
+				if event.Error != "" {
+					log.Println("Run failed:", event.Error)
+				}
+				reason, period = event.Reason, event.Period
+				if event.Reason != "" {
+					fmt.Printf("Stopped after %d turns with %d cells alive: %s\n", event.CompletedTurns, event.CellsCount, stubs.DescribeStop(event.Reason, event.Period))
+				}
+				running = false
+			}
 		}
-	case <-processingDone:
 	}
+	// Stop following events before the final events and closing c.events.
+	close(finished)
 
-	finalWorldRequest := &stubs.GetWorldRequest{}
-	finalWorldResponse := new(stubs.GetWorldResponse)
-	err = client.Call(stubs.GetWorld, finalWorldRequest, finalWorldResponse)
+	world, turn, _, err := engine.Snapshot()
 	if err != nil {
 		log.Println("Error calling GetWorld:", err)
 	} else {
-		world = finalWorldResponse.World
-		turn := finalWorldResponse.CompletedTurns
-
 		aliveCells := []util.Cell{}
 		for y := 0; y < p.ImageHeight; y++ {
 			for x := 0; x < p.ImageWidth; x++ {
@@ -239,6 +280,8 @@ func distributor(p Params, c distributorChannels, keyPresses <-chan rune) {
 		c.events <- FinalTurnComplete{
 			CompletedTurns: turn,
 			Alive:          aliveCells,
+			Reason:         reason,
+			Period:         period,
 		}
 
 		c.events <- StateChange{
@@ -0,0 +1,244 @@
+package gol
+
+import (
+	"fmt"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+)
+
+// Engine evolves the world on behalf of the distributor. The distributor only
+// ever talks to an Engine, so every backend produces the same Events.
+type Engine interface {
+	// Start begins evolving world for p.Turns turns in the background,
+	// replacing any run already in progress.
+	Start(p Params, world [][]uint8) error
+	// Pause suspends the run and returns the number of completed turns.
+	Pause() (int, error)
+	// Resume continues a paused run.
+	Resume() error
+	// Step runs n more turns of a paused run and pauses it again, returning
+	// the turn it will pause at.
+	Step(n int) (int, error)
+	// SetSpeed caps the run at turnsPerSecond turns a second, or lifts the cap
+	// if it is 0.
+	SetSpeed(turnsPerSecond float64) error
+	// Snapshot returns a copy of the current world, the number of completed
+	// turns and whether the run is still processing.
+	Snapshot() ([][]uint8, int, bool, error)
+	// AliveCount returns the number of alive cells and completed turns.
+	AliveCount() (int, int, error)
+	// Subscribe returns the events of the run after the one numbered after,
+	// waiting up to wait for one to happen. See stubs.RunEvent.
+	Subscribe(after uint64, wait time.Duration) ([]stubs.RunEvent, error)
+	// Stop ends the run, keeping the last completed world available.
+	Stop() error
+	// Shutdown ends the run and asks the backend to shut down.
+	Shutdown() error
+	// Close releases the connection to the backend.
+	Close() error
+}
+
+// NewEngine returns the engine selected by p: a remote broker when p.Broker
+// holds its address, and the local multi-threaded engine otherwise.
+func NewEngine(p Params) (Engine, error) {
+	if p.Broker == "" {
+		return newLocalEngine(), nil
+	}
+	return dialRemoteEngine(p)
+}
+
+// remoteEngine drives a broker over net/rpc, sending worlds in the encoding
+// agreed with the broker. It keeps a mirror of the broker's world, taken at
+// turn of run after edits edits, and brings it up to date with GetWorldDelta. started is the run
+// Start began, whose events Subscribe follows.
+type remoteEngine struct {
+	client   *rpc.Client
+	encoding string
+	token    string
+	timeout  time.Duration
+	started  int
+
+	mu      sync.Mutex
+	mirror  []uint8
+	run     int
+	turn    int
+	edits   int
+	noDelta bool // the broker does not offer GetWorldDelta
+}
+
+// dialRemoteEngine connects to the broker in p and checks, through Hello, that
+// it can run p.
+func dialRemoteEngine(p Params) (*remoteEngine, error) {
+	tlsConfig, err := stubs.TLSFiles{Cert: p.TLSCert, Key: p.TLSKey, CA: p.TLSCA}.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := stubs.Dial(p.Broker, tlsConfig, p.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	client := rpc.NewClient(conn)
+	hello := &stubs.HelloRequest{
+		Version:  stubs.ProtocolVersion,
+		Rules:    stubs.Rules,
+		MaxCells: stubs.MaxCells,
+		Features: []string{stubs.FeatureWorldDelta},
+		Token:    p.Token,
+	}
+	if p.Compress {
+		hello.Encodings = stubs.Encodings
+	}
+	response, err := stubs.SayHello(stubs.CallerTimeout(client, p.Timeout), stubs.Hello, "broker at "+p.Broker, hello)
+	if err == nil && response.MaxCells > 0 && p.ImageWidth*p.ImageHeight > response.MaxCells {
+		err = fmt.Errorf("broker at %s accepts at most %d cells, too few for a %dx%d world", p.Broker, response.MaxCells, p.ImageWidth, p.ImageHeight)
+	}
+	if err == nil && !stubs.HasFeature(response.Features, stubs.FeatureEvents) {
+		err = fmt.Errorf("broker at %s does not push events, is it running an older version?", p.Broker)
+	}
+	if err == nil && response.Role == stubs.RoleObserver {
+		err = fmt.Errorf("the token only lets this controller observe the broker at %s; running a world needs a controller token", p.Broker)
+	}
+	if err == nil && p.Probability > 0 && p.Probability < 1 && !stubs.HasFeature(response.Features, stubs.FeatureNoisy) {
+		err = fmt.Errorf("broker at %s does not support noisy Life", p.Broker)
+	}
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &remoteEngine{
+		client:   client,
+		encoding: response.Encoding,
+		token:    p.Token,
+		timeout:  p.Timeout,
+		noDelta:  !stubs.HasFeature(response.Features, stubs.FeatureWorldDelta),
+	}, nil
+}
+
+func (e *remoteEngine) Start(p Params, world [][]uint8) error {
+	request := &stubs.EngineRequest{
+		World:       world,
+		ImageWidth:  p.ImageWidth,
+		ImageHeight: p.ImageHeight,
+		Turns:       p.Turns,
+		Threads:     p.Threads,
+		Seed:        p.Seed,
+		Probability: p.Probability,
+		Token:       e.token,
+		Until:       p.Until,
+	}
+	if e.encoding != stubs.EncodingRaw {
+		cells, err := stubs.EncodeWorld(e.encoding, world)
+		if err != nil {
+			return err
+		}
+		request.World, request.Encoding, request.Cells = nil, e.encoding, cells
+	}
+	response := new(stubs.EngineResponse)
+	if err := e.call(stubs.Process, request, response); err != nil {
+		return err
+	}
+	e.started = response.Run
+	return nil
+}
+
+func (e *remoteEngine) Pause() (int, error) {
+	response := new(stubs.PauseResponse)
+	if err := e.call(stubs.Pause, &stubs.PauseRequest{Token: e.token}, response); err != nil {
+		return 0, err
+	}
+	return response.Turn, nil
+}
+
+func (e *remoteEngine) Resume() error {
+	return e.call(stubs.Resume, &stubs.ResumeRequest{Token: e.token}, new(stubs.ResumeResponse))
+}
+
+func (e *remoteEngine) Step(n int) (int, error) {
+	response := new(stubs.StepResponse)
+	if err := e.call(stubs.Step, &stubs.StepRequest{Turns: n, Token: e.token}, response); err != nil {
+		return 0, err
+	}
+	return response.Turn, nil
+}
+
+func (e *remoteEngine) SetSpeed(turnsPerSecond float64) error {
+	return e.call(stubs.SetSpeed, &stubs.SetSpeedRequest{TurnsPerSecond: turnsPerSecond, Token: e.token}, new(stubs.SetSpeedResponse))
+}
+
+func (e *remoteEngine) Snapshot() ([][]uint8, int, bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.noDelta {
+		response := new(stubs.GetWorldDeltaResponse)
+		request := &stubs.GetWorldDeltaRequest{Run: e.run, SinceTurn: e.turn, SinceEdits: e.edits, Encoding: e.encoding, Token: e.token}
+		if err := e.call(stubs.GetWorldDelta, request, response); err != nil {
+			return nil, 0, false, err
+		}
+		var err error
+		e.mirror, err = stubs.ApplyWorldDelta(e.mirror, response)
+		if err != nil {
+			// Start again from a whole world next time.
+			e.run = 0
+			return nil, 0, false, err
+		}
+		e.run, e.turn, e.edits = response.Run, response.CompletedTurns, response.Edits
+		world := make([][]uint8, response.ImageHeight)
+		for y := range world {
+			world[y] = make([]uint8, response.ImageWidth)
+			copy(world[y], e.mirror[y*response.ImageWidth:])
+		}
+		return world, response.CompletedTurns, response.Processing, nil
+	}
+
+	response := new(stubs.GetWorldResponse)
+	if err := e.call(stubs.GetWorld, &stubs.GetWorldRequest{Encoding: e.encoding, Token: e.token}, response); err != nil {
+		return nil, 0, false, err
+	}
+	var err error
+	if response.Encoding != stubs.EncodingRaw {
+		response.World, err = stubs.DecodeWorld(response.Encoding, response.Cells, response.ImageWidth, response.ImageHeight)
+	}
+	return response.World, response.CompletedTurns, response.Processing, err
+}
+
+func (e *remoteEngine) AliveCount() (int, int, error) {
+	response := new(stubs.AliveCellsCountResponse)
+	if err := e.call(stubs.GetAliveCells, &stubs.AliveCellsCountRequest{Token: e.token}, response); err != nil {
+		return 0, 0, err
+	}
+	return response.CellsCount, response.CompletedTurns, nil
+}
+
+func (e *remoteEngine) Subscribe(after uint64, wait time.Duration) ([]stubs.RunEvent, error) {
+	request := &stubs.SubscribeRequest{Run: e.started, After: after, Wait: wait, Token: e.token}
+	response := new(stubs.SubscribeResponse)
+	timeout := e.timeout
+	if timeout > 0 {
+		timeout += wait
+	}
+	if err := stubs.CallTimeout(e.client, timeout, stubs.Subscribe, request, response); err != nil {
+		return nil, err
+	}
+	return response.Events, nil
+}
+
+func (e *remoteEngine) Stop() error {
+	return e.call(stubs.StopProcessing, &stubs.StopRequest{Token: e.token}, new(stubs.StopResponse))
+}
+
+func (e *remoteEngine) Shutdown() error {
+	return e.call(stubs.Shutdown, &stubs.ShutdownRequest{Token: e.token}, new(stubs.ShutdownResponse))
+}
+
+func (e *remoteEngine) Close() error {
+	return e.client.Close()
+}
+
+// call sends one request to the broker, giving up after e.timeout. After a
+// timeout the reply may still be written, so callers must not read it.
+func (e *remoteEngine) call(serviceMethod string, args, reply interface{}) error {
+	return stubs.CallTimeout(e.client, e.timeout, serviceMethod, args, reply)
+}
@@ -22,11 +22,21 @@ type AliveCellsCount struct { // implements Event
 	CellsCount     int
 }
 
+// `SpeedChange` is an Event notifying the user that the target speed of the run changed.
+// A TurnsPerSecond of 0 means the run is no longer held back.
+type SpeedChange struct { // implements Event
+	CompletedTurns int
+	TurnsPerSecond float64
+}
+
 // `ImageOutputComplete` is an Event notifying the user about the completion of output.
 // This Event should be sent every time an image has been saved.
+// Filename is the name of the image without its directory or extension, and
+// Path the file it was saved to.
 type ImageOutputComplete struct { // implements Event
 	CompletedTurns int
 	Filename       string
+	Path           string
 }
 
 // State represents a change in the state of execution.
@@ -73,9 +83,14 @@ type TurnComplete struct { // implements Event
 // `FinalTurnComplete` is an Event notifying the testing framework about the new world state after execution finished.
 // The data included with this Event is used directly by the tests.
 // SDL closes the window when this Event is sent.
+// Reason is empty when the run completed its turns or was stopped by a key, and
+// otherwise says which of Params.Until stopped it, with Period the period of
+// the cycle it found; see stubs.DescribeStop.
 type FinalTurnComplete struct {
 	CompletedTurns int
 	Alive          []util.Cell
+	Reason         string
+	Period         int
 }
 
 // String methods allow the different types of Events and States to be printed.
@@ -109,8 +124,19 @@ func (event AliveCellsCount) GetCompletedTurns() int {
 	return event.CompletedTurns
 }
 
+func (event SpeedChange) String() string {
+	if event.TurnsPerSecond == 0 {
+		return "Speed Unlimited"
+	}
+	return fmt.Sprintf("Speed %v turns/sec", event.TurnsPerSecond)
+}
+
+func (event SpeedChange) GetCompletedTurns() int {
+	return event.CompletedTurns
+}
+
 func (event ImageOutputComplete) String() string {
-	return fmt.Sprintf("File %v Output Done", event.Filename)
+	return fmt.Sprintf("File %v Output Done", event.Path)
 }
 
 func (event ImageOutputComplete) GetCompletedTurns() int {
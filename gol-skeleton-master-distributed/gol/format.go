@@ -0,0 +1,557 @@
+package gol
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+)
+
+// pattern is a rectangle of cells read from or written to a file, flattened row
+// by row, with 255 for alive and 0 for dead. Images read hold grey levels
+// instead, which readImage tells apart against Params.Threshold.
+type pattern struct {
+	width  int
+	height int
+	cells  []uint8
+}
+
+// worldFormat reads and writes patterns in one file format. sniff tells the
+// format from the start of a file whose extension is not known. Formats that
+// can only be read have no write.
+type worldFormat struct {
+	name      string
+	extension string
+	sniff     func(data []byte) bool
+	read      func(data []byte) (*pattern, error)
+	write     func(w io.Writer, p *pattern) error
+	// size reads the width and height from the header alone. Formats without
+	// one leave it nil and are read in full.
+	size func(data []byte) (width, height int, err error)
+}
+
+// worldFormats lists the formats the io goroutine understands. The first is
+// the default for output.
+var worldFormats = []worldFormat{
+	{name: "pgm", extension: ".pgm", sniff: isPgm, read: readPgm, write: writePgm, size: pgmSize},
+	{name: "rle", extension: ".rle", sniff: isRLE, read: readRLE, write: writeRLE, size: rleSize},
+	{name: "cells", extension: ".cells", sniff: isCells, read: readCells, write: writeCells},
+	{name: "lif", extension: ".lif", sniff: isLife106, read: readLife106, write: writeLife106},
+	{name: "png", extension: ".png", sniff: hasMagic("\x89PNG"), read: readPicture, write: writePng, size: pictureSize},
+	{name: "gif", extension: ".gif", sniff: hasMagic("GIF8"), read: readPicture, size: pictureSize},
+	{name: "jpeg", extension: ".jpg", sniff: hasMagic("\xff\xd8\xff"), read: readPicture, size: pictureSize},
+}
+
+// formatNamed returns the format called name to write in, or the default one if
+// name is empty.
+func formatNamed(name string) (worldFormat, error) {
+	if name == "" {
+		return worldFormats[0], nil
+	}
+	for _, f := range worldFormats {
+		if f.name == name && f.write != nil {
+			return f, nil
+		}
+	}
+	return worldFormat{}, fmt.Errorf("cannot write in format %q", name)
+}
+
+// formatOf returns the format of data read from path, from its extension or
+// failing that from its first lines.
+func formatOf(path string, data []byte) (worldFormat, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, f := range worldFormats {
+		if f.extension == ext {
+			return f, nil
+		}
+	}
+	for _, f := range worldFormats {
+		if f.sniff(data) {
+			return f, nil
+		}
+	}
+	return worldFormat{}, fmt.Errorf("%s: unknown file format", path)
+}
+
+// firstLine returns the first line of data that is not blank and, if comment
+// is set, does not start with it.
+func firstLine(data []byte, comment string) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && (comment == "" || !strings.HasPrefix(line, comment)) {
+			return line
+		}
+	}
+	return ""
+}
+
+// checkSize refuses a width by height pattern larger than a world can be.
+func checkSize(width, height int) error {
+	if width < 0 || height < 0 || (height > 0 && width > stubs.MaxCells/height) {
+		return fmt.Errorf("a %dx%d pattern is too large", width, height)
+	}
+	return nil
+}
+
+// newPattern returns a dead width by height pattern, refusing sizes larger
+// than a world can be.
+func newPattern(width, height int) (*pattern, error) {
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
+	return &pattern{width: width, height: height, cells: make([]uint8, width*height)}, nil
+}
+
+// place copies p into a width by height world with its top left cell at (x,
+// y), wrapping around the edges as the world does.
+func (p *pattern) place(width, height, x, y int) ([]uint8, error) {
+	if p.width > width || p.height > height {
+		return nil, fmt.Errorf("a %dx%d pattern does not fit in a %dx%d world", p.width, p.height, width, height)
+	}
+	world := make([]uint8, width*height)
+	x, y = (x%width+width)%width, (y%height+height)%height
+	for py := 0; py < p.height; py++ {
+		row := world[(y+py)%height*width:]
+		for px, cell := range p.cells[py*p.width : (py+1)*p.width] {
+			row[(x+px)%width] = cell
+		}
+	}
+	return world, nil
+}
+
+func isPgm(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("P5"))
+}
+
+// readPgm reads a binary PGM with a maximum value of 255.
+func readPgm(data []byte) (*pattern, error) {
+	width, height, rest, err := pgmHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	p, err := newPattern(width, height)
+	if err != nil {
+		return nil, err
+	}
+	// A single whitespace character separates the header from the cells.
+	if len(rest) < 1+len(p.cells) {
+		return nil, fmt.Errorf("pgm file holds fewer than %d cells", len(p.cells))
+	}
+	copy(p.cells, rest[1:])
+	return p, nil
+}
+
+func pgmSize(data []byte) (width, height int, err error) {
+	width, height, _, err = pgmHeader(data)
+	return width, height, err
+}
+
+// pgmHeader reads the header of a binary PGM, returning the size and the data
+// after it.
+func pgmHeader(data []byte) (width, height int, rest []byte, err error) {
+	var header [4]int
+	rest = data
+	for i := range header {
+		// Skip whitespace and comments before each field.
+		for len(rest) > 0 && (isSpace(rest[0]) || rest[0] == '#') {
+			if rest[0] == '#' {
+				if end := bytes.IndexByte(rest, '\n'); end >= 0 {
+					rest = rest[end:]
+				} else {
+					rest = nil
+				}
+				continue
+			}
+			rest = rest[1:]
+		}
+		end := 0
+		for end < len(rest) && !isSpace(rest[end]) {
+			end++
+		}
+		field := string(rest[:end])
+		rest = rest[end:]
+		if i == 0 {
+			if field != "P5" {
+				return 0, 0, nil, errors.New("not a pgm file")
+			}
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("bad pgm header field %q", field)
+		}
+		header[i] = n
+	}
+	if header[3] != 255 {
+		return 0, 0, nil, errors.New("incorrect maxval/bit depth")
+	}
+	return header[1], header[2], rest, nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// writePgm writes p as a binary PGM.
+func writePgm(w io.Writer, p *pattern) error {
+	if _, err := fmt.Fprintf(w, "P5\n%d %d\n255\n", p.width, p.height); err != nil {
+		return err
+	}
+	_, err := w.Write(p.cells)
+	return err
+}
+
+// lifeRules are the spellings of Conway's rule an RLE file may use.
+var lifeRules = map[string]bool{"b3/s23": true, "23/3": true, "s23/b3": true}
+
+func isRLE(data []byte) bool {
+	return strings.HasPrefix(strings.ReplaceAll(firstLine(data, "#"), " ", ""), "x=")
+}
+
+// readRLE reads a pattern in Golly's run-length encoded format: comment lines
+// starting with #, a header line such as "x = 3, y = 3, rule = B3/S23", then
+// runs of dead (b) and alive (o) cells, with $ ending a row and ! the pattern.
+func readRLE(data []byte) (*pattern, error) {
+	width, height, lines, err := rleHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	p, err := newPattern(width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y, count := 0, 0, 0
+	for _, c := range bytes.Join(lines, nil) {
+		switch {
+		case c >= '0' && c <= '9':
+			count = count*10 + int(c-'0')
+			if count > stubs.MaxCells {
+				return nil, errors.New("rle run is too long")
+			}
+			continue
+		case isSpace(c):
+			continue
+		}
+		n := count
+		if n == 0 {
+			n = 1
+		}
+		count = 0
+		switch c {
+		case 'b', 'o':
+			if x+n > p.width || y >= p.height {
+				return nil, fmt.Errorf("rle cells run past the %dx%d header", p.width, p.height)
+			}
+			if c == 'o' {
+				for i := 0; i < n; i++ {
+					p.cells[y*p.width+x+i] = 255
+				}
+			}
+			x += n
+		case '$':
+			x, y = 0, y+n
+		case '!':
+			return p, nil
+		default:
+			return nil, fmt.Errorf("unsupported rle tag %q", c)
+		}
+	}
+	return nil, errors.New("rle pattern does not end with !")
+}
+
+func rleSize(data []byte) (width, height int, err error) {
+	width, height, _, err = rleHeader(data)
+	return width, height, err
+}
+
+// rleHeader reads the header line of an RLE file, skipping the comments
+// before it, and returns the size and the lines after it.
+func rleHeader(data []byte) (width, height int, rest [][]byte, err error) {
+	lines := bytes.Split(data, []byte("\n"))
+	for len(lines) > 0 {
+		line := strings.TrimSpace(string(lines[0]))
+		lines = lines[1:]
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		width, height = -1, -1
+		for _, field := range strings.Split(line, ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return 0, 0, nil, fmt.Errorf("bad rle header %q", line)
+			}
+			key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+			var err error
+			switch key {
+			case "x":
+				width, err = strconv.Atoi(value)
+			case "y":
+				height, err = strconv.Atoi(value)
+			case "rule":
+				if !lifeRules[strings.ToLower(value)] {
+					return 0, 0, nil, fmt.Errorf("rule %s is not supported, only %s", value, stubs.RuleLife)
+				}
+			}
+			if err != nil {
+				return 0, 0, nil, fmt.Errorf("bad rle header %q", line)
+			}
+		}
+		if width < 0 || height < 0 {
+			return 0, 0, nil, fmt.Errorf("rle header %q lacks x or y", line)
+		}
+		return width, height, lines, nil
+	}
+	return 0, 0, nil, errors.New("rle file has no header")
+}
+
+// rleLineLength is how long Golly keeps the lines of an RLE file.
+const rleLineLength = 70
+
+// writeRLE writes p in run-length encoded format, leaving out the dead cells
+// at the end of each row and the empty rows at the bottom.
+func writeRLE(w io.Writer, p *pattern) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "x = %d, y = %d, rule = %s\n", p.width, p.height, stubs.RuleLife)
+	line := 0
+	emit := func(n int, tag byte) {
+		token := string(tag)
+		if n > 1 {
+			token = strconv.Itoa(n) + token
+		}
+		if line+len(token) > rleLineLength {
+			bw.WriteByte('\n')
+			line = 0
+		}
+		bw.WriteString(token)
+		line += len(token)
+	}
+	rows := 0 // row ends not yet written
+	for y := 0; y < p.height; y++ {
+		row := p.cells[y*p.width : (y+1)*p.width]
+		end := len(row)
+		for end > 0 && row[end-1] == 0 {
+			end--
+		}
+		if end == 0 {
+			rows++
+			continue
+		}
+		if rows > 0 {
+			emit(rows, '$')
+		}
+		for x := 0; x < end; {
+			run := 1
+			for x+run < end && (row[x+run] != 0) == (row[x] != 0) {
+				run++
+			}
+			tag := byte('b')
+			if row[x] != 0 {
+				tag = 'o'
+			}
+			emit(run, tag)
+			x += run
+		}
+		rows = 1
+	}
+	emit(1, '!')
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+func isCells(data []byte) bool {
+	line := firstLine(data, "")
+	return strings.HasPrefix(line, "!") || line != "" && strings.Trim(line, ".O*") == ""
+}
+
+// readCells reads a pattern in plaintext format: comment lines starting with !,
+// then one line per row with . for dead and O (or *) for alive cells. Rows may
+// stop short after their last alive cell.
+func readCells(data []byte) (*pattern, error) {
+	var rows []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if !strings.HasPrefix(line, "!") {
+			rows = append(rows, line)
+		}
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	p, err := newPattern(width, len(rows))
+	if err != nil {
+		return nil, err
+	}
+	for y, row := range rows {
+		for x, c := range []byte(row) {
+			switch c {
+			case '.':
+			case 'O', '*':
+				p.cells[y*width+x] = 255
+			default:
+				return nil, fmt.Errorf("unexpected %q in row %d of a plaintext pattern", c, y+1)
+			}
+		}
+	}
+	return p, nil
+}
+
+// writeCells writes p in plaintext format, every row in full so that the size
+// of the pattern survives.
+func writeCells(w io.Writer, p *pattern) error {
+	bw := bufio.NewWriter(w)
+	row := make([]byte, p.width+1)
+	row[p.width] = '\n'
+	for y := 0; y < p.height; y++ {
+		for x, cell := range p.cells[y*p.width : (y+1)*p.width] {
+			row[x] = '.'
+			if cell != 0 {
+				row[x] = 'O'
+			}
+		}
+		bw.Write(row)
+	}
+	return bw.Flush()
+}
+
+// life106Header starts every Life 1.06 file.
+const life106Header = "#Life 1.06"
+
+func isLife106(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(life106Header))
+}
+
+// readLife106 reads a pattern in Life 1.06 format: the header line, then the
+// x and y coordinates of one alive cell per line. Coordinates may be negative,
+// so the pattern is moved to start at (0, 0) and is only as large as its alive
+// cells.
+func readLife106(data []byte) (*pattern, error) {
+	if !isLife106(data) {
+		return nil, fmt.Errorf("life 1.06 file does not start with %q", life106Header)
+	}
+	var cells [][2]int
+	minX, minY, maxX, maxY := 0, 0, -1, -1
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		x, errX := strconv.Atoi(fields[0])
+		y, errY := strconv.Atoi(fields[len(fields)-1])
+		if len(fields) != 2 || errX != nil || errY != nil {
+			return nil, fmt.Errorf("bad cell %q on line %d", line, i+1)
+		}
+		if len(cells) == 0 {
+			minX, minY, maxX, maxY = x, y, x, y
+		}
+		minX, maxX = widen(x, minX, maxX)
+		minY, maxY = widen(y, minY, maxY)
+		cells = append(cells, [2]int{x, y})
+	}
+	if maxX-minX >= stubs.MaxCells || maxY-minY >= stubs.MaxCells {
+		return nil, errors.New("life 1.06 cells are too far apart")
+	}
+	p, err := newPattern(maxX-minX+1, maxY-minY+1)
+	if err != nil {
+		return nil, err
+	}
+	for _, cell := range cells {
+		p.cells[(cell[1]-minY)*p.width+cell[0]-minX] = 255
+	}
+	return p, nil
+}
+
+// widen widens lo and hi to take in v.
+func widen(v, lo, hi int) (int, int) {
+	if v < lo {
+		lo = v
+	}
+	if v > hi {
+		hi = v
+	}
+	return lo, hi
+}
+
+// writeLife106 writes the alive cells of p in Life 1.06 format. The format
+// has no size, so reading it back gives only the box round the alive cells.
+func writeLife106(w io.Writer, p *pattern) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(life106Header + "\n")
+	for i, cell := range p.cells {
+		if cell != 0 {
+			fmt.Fprintf(bw, "%d %d\n", i%p.width, i/p.width)
+		}
+	}
+	return bw.Flush()
+}
+
+// hasMagic returns a sniff for files starting with magic.
+func hasMagic(magic string) func(data []byte) bool {
+	return func(data []byte) bool {
+		return bytes.HasPrefix(data, []byte(magic))
+	}
+}
+
+// readPicture decodes a png, gif or jpeg into the grey level of each pixel.
+func readPicture(data []byte) (*pattern, error) {
+	// Check the size in the header before decoding allocates for it.
+	width, height, err := pictureSize(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
+	picture, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	bounds := picture.Bounds()
+	grey, ok := picture.(*image.Gray)
+	if !ok {
+		grey = image.NewGray(bounds)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				grey.Set(x, y, picture.At(x, y))
+			}
+		}
+	}
+	width, height = bounds.Dx(), bounds.Dy()
+	if grey.Stride == width && bounds.Min == (image.Point{}) {
+		return &pattern{width: width, height: height, cells: grey.Pix[:width*height]}, nil
+	}
+	p, err := newPattern(width, height)
+	if err != nil {
+		return nil, err
+	}
+	for y := 0; y < height; y++ {
+		copy(p.cells[y*width:(y+1)*width], grey.Pix[grey.PixOffset(bounds.Min.X, bounds.Min.Y+y):])
+	}
+	return p, nil
+}
+
+func pictureSize(data []byte) (width, height int, err error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	return config.Width, config.Height, err
+}
+
+// writePng writes p as a greyscale png, alive cells white.
+func writePng(w io.Writer, p *pattern) error {
+	return png.Encode(w, &image.Gray{Pix: p.cells, Stride: p.width, Rect: image.Rect(0, 0, p.width, p.height)})
+}
@@ -0,0 +1,217 @@
+package gol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestRLE reads a glider with comments and a split line, writes it back, and
+// checks both the text and the cells survive.
+func TestRLE(t *testing.T) {
+	glider, err := readRLE([]byte("#N Glider\n#C A comment.\nx = 3, y = 4, rule = B3/S23\nbo$2b\no$3o!\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint8{0, 255, 0, 0, 0, 255, 255, 255, 255, 0, 0, 0}
+	if glider.width != 3 || glider.height != 4 || !bytes.Equal(glider.cells, want) {
+		t.Fatalf("expected a 3x4 glider %v, got %dx%d %v", want, glider.width, glider.height, glider.cells)
+	}
+
+	var out bytes.Buffer
+	if err := writeRLE(&out, glider); err != nil {
+		t.Fatal(err)
+	}
+	if text := "x = 3, y = 4, rule = B3/S23\nbo$2bo$3o!\n"; out.String() != text {
+		t.Errorf("expected\n%s\ngot\n%s", text, out.String())
+	}
+	again, err := readRLE(out.Bytes())
+	if err != nil || !bytes.Equal(again.cells, glider.cells) {
+		t.Errorf("cells changed in a round trip: %v, %v", again, err)
+	}
+
+	world, err := glider.place(4, 4, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Placed at (2, 3), the glider wraps round both edges.
+	alive := []int{3*4 + 3, 0*4 + 0, 1*4 + 2, 1*4 + 3, 1*4 + 0}
+	for _, i := range alive {
+		if world[i] != 255 {
+			t.Errorf("expected cell (%d, %d) to be alive", i%4, i/4)
+		}
+	}
+	if count := bytes.Count(world, []byte{255}); count != len(alive) {
+		t.Errorf("expected %d alive cells, got %d", len(alive), count)
+	}
+
+	for _, bad := range []string{
+		"x = 3, y = 3, rule = B36/S23\nobo!",
+		"x = 2, y = 1\n3o!",
+		"x = 3, y = 3\nobo$",
+		"bo$obo!",
+	} {
+		if _, err := readRLE([]byte(bad)); err == nil {
+			t.Errorf("expected %q to be refused", bad)
+		}
+	}
+}
+
+// TestPgm checks that the images shipped with the skeleton read back unchanged.
+func TestPgm(t *testing.T) {
+	data, err := os.ReadFile("../images/16x16.pgm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	image, err := readPgm(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image.width != 16 || image.height != 16 {
+		t.Fatalf("expected 16x16, got %dx%d", image.width, image.height)
+	}
+	var out bytes.Buffer
+	if err := writePgm(&out, image); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Error("pgm changed in a round trip")
+	}
+}
+
+// TestPlaintext reads a glider in plaintext and Life 1.06 formats, the latter
+// with negative coordinates, and checks both write back what they read.
+func TestPlaintext(t *testing.T) {
+	want := []uint8{0, 255, 0, 0, 0, 255, 255, 255, 255}
+	for _, test := range []struct {
+		name, text, written string
+	}{
+		{"cells", "!Name: Glider\n.O\n..O\nOOO\n", ".O.\n..O\nOOO\n"},
+		{"lif", "#Life 1.06\n#D A comment.\n0 -1\n1 0\n-1 1\n0 1\n1 1\n", "#Life 1.06\n1 0\n2 1\n0 2\n1 2\n2 2\n"},
+	} {
+		format, err := formatOf("glider", []byte(test.text))
+		if err != nil || format.name != test.name {
+			t.Errorf("%s: told the format as %q, %v", test.name, format.name, err)
+			continue
+		}
+		glider, err := format.read([]byte(test.text))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if glider.width != 3 || glider.height != 3 || !bytes.Equal(glider.cells, want) {
+			t.Errorf("%s: expected a 3x3 glider %v, got %dx%d %v", test.name, want, glider.width, glider.height, glider.cells)
+		}
+		var out bytes.Buffer
+		if err := format.write(&out, glider); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != test.written {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.written, out.String())
+		}
+	}
+
+	if format, _ := formatOf("glider", []byte("#C made by hand\nx = 3, y = 3\nbo$2bo$3o!")); format.name != "rle" {
+		t.Errorf("expected an rle header to be told apart, got %q", format.name)
+	}
+	if _, err := readCells([]byte(".O\nxO\n")); err == nil {
+		t.Error("expected a plaintext pattern with an x to be refused")
+	}
+	if _, err := readLife106([]byte("#Life 1.05\n.O\n")); err == nil {
+		t.Error("expected a Life 1.05 file to be refused")
+	}
+}
+
+// TestPng writes a world as a png and checks it decodes back to the same cells,
+// that a gif decodes too, that gif and jpeg cannot be written and that an
+// oversized picture is refused.
+func TestPng(t *testing.T) {
+	world := &pattern{width: 3, height: 2, cells: []uint8{0, 255, 0, 255, 0, 0}}
+	var out bytes.Buffer
+	if err := writePng(&out, world); err != nil {
+		t.Fatal(err)
+	}
+	format, err := formatOf("world", out.Bytes())
+	if err != nil || format.name != "png" {
+		t.Fatalf("told the format as %q, %v", format.name, err)
+	}
+	again, err := format.read(out.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.width != 3 || again.height != 2 || !bytes.Equal(again.cells, world.cells) {
+		t.Errorf("expected a 3x2 world %v, got %dx%d %v", world.cells, again.width, again.height, again.cells)
+	}
+	for _, name := range []string{"gif", "jpeg"} {
+		if _, err := formatNamed(name); err == nil {
+			t.Errorf("expected %s to be refused for output", name)
+		}
+	}
+
+	// A paletted gif goes through the slow path for pictures that are not grey.
+	var palette bytes.Buffer
+	paletted := image.NewPaletted(image.Rect(0, 0, 3, 2), color.Palette{color.Black, color.White})
+	for i, cell := range world.cells {
+		paletted.Pix[i] = cell / 255
+	}
+	if err := gif.Encode(&palette, paletted, nil); err != nil {
+		t.Fatal(err)
+	}
+	again, err = readPicture(palette.Bytes())
+	if err != nil || !bytes.Equal(again.cells, world.cells) {
+		t.Errorf("expected the gif to read as %v, got %v, %v", world.cells, again, err)
+	}
+
+	// A header claiming 100000x100000 pixels is refused without decoding.
+	huge := append([]byte(nil), out.Bytes()...)
+	ihdr := huge[12:29]
+	binary.BigEndian.PutUint32(ihdr[4:], 100000)
+	binary.BigEndian.PutUint32(ihdr[8:], 100000)
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(ihdr))
+	if _, err := readPicture(huge); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("expected a 100000x100000 png to be refused, got %v", err)
+	}
+}
+
+// TestReadSize checks that the size of a world is taken from files of any
+// format, from the header alone where there is one, and that a pattern
+// without cells or too large for a world is refused.
+func TestReadSize(t *testing.T) {
+	dir := t.TempDir()
+	var picture bytes.Buffer
+	if err := writePng(&picture, &pattern{width: 5, height: 2, cells: make([]uint8, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		path, text    string
+		width, height int
+	}{
+		{"../images/64x64.pgm", "", 64, 64},
+		{dir + "/glider", "x = 3, y = 4\nbo$2bo$3o!\n", 3, 4},
+		{dir + "/unread.rle", "x = 3, y = 4\nnot read", 3, 4},
+		{dir + "/world.png", picture.String(), 5, 2},
+		{dir + "/glider.cells", ".O\n..O\nOOO\n", 3, 3},
+		{dir + "/empty.lif", "#Life 1.06\n", 0, 0},
+		{dir + "/huge.pgm", "P5 100000 100000 255\n", 0, 0},
+	} {
+		if test.text != "" {
+			if err := os.WriteFile(test.path, []byte(test.text), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		width, height, err := ReadSize(test.path)
+		if test.width == 0 {
+			if err == nil {
+				t.Errorf("%s: expected the pattern to be refused", test.path)
+			}
+		} else if err != nil || width != test.width || height != test.height {
+			t.Errorf("%s: expected %dx%d, got %dx%d, %v", test.path, test.width, test.height, width, height, err)
+		}
+	}
+}
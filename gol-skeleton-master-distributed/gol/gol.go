@@ -1,11 +1,63 @@
 package gol
 
+import (
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
 // Params provides the details of how to run the Game of Life and which image to load.
 type Params struct {
 	Turns       int
 	Threads     int
 	ImageWidth  int
 	ImageHeight int
+	// Seed keys the random draws of a run with a Probability, making it
+	// reproducible.
+	Seed uint64
+	// Probability, when strictly between 0 and 1, runs noisy Life, where each
+	// transition only happens with that probability.
+	Probability float64
+	// Broker is the address of a remote broker. When empty the turns are
+	// computed in this process using Threads goroutines.
+	Broker string
+	// Compress offers the broker encoded worlds instead of raw ones.
+	Compress bool
+	// TLSCert and TLSKey, when set, are the certificate and key to connect to
+	// the broker over TLS with.
+	TLSCert string
+	TLSKey  string
+	// TLSCA is the CA the broker's certificate is checked against.
+	TLSCA string
+	// Token is sent with every request to a broker that checks tokens.
+	Token string
+	// Timeout bounds every call to the broker; 0 waits for ever.
+	Timeout time.Duration
+	// Until ends the run before Turns once one of its conditions holds.
+	Until stubs.StopConditions
+	// Pattern, when set, is the path of a file to start from instead of
+	// images/WxH.pgm: pgm, rle, cells, Life 1.06, png, gif or jpeg.
+	Pattern string
+	// PatternAt is where the top left cell of Pattern goes. When nil the
+	// pattern is placed in the middle of the world.
+	PatternAt *util.Cell
+	// OutputFormat names the format of the files written: pgm, the default,
+	// rle, cells, lif or png.
+	OutputFormat string
+	// Threshold is the grey level from which a pixel of an image read is
+	// alive; 0 means 128.
+	Threshold uint8
+	// OutDir is the directory files are written to, out by default.
+	OutDir string
+}
+
+// outDir returns the directory output files are written to.
+func (p Params) outDir() string {
+	if p.OutDir == "" {
+		return "out"
+	}
+	return p.OutDir
 }
 
 // Run starts the processing of Game of Life. It should initialise channels and goroutines.
@@ -0,0 +1,72 @@
+package gol
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+)
+
+// TestFinalReason runs patterns until one of their stop conditions holds and
+// checks that FinalTurnComplete says which, and with which period.
+func TestFinalReason(t *testing.T) {
+	dir := t.TempDir()
+	for _, test := range []struct {
+		name, rle     string
+		until         stubs.StopConditions
+		turns, period int
+		reason        string
+	}{
+		{"blinker", "x = 3, y = 1\n3o!", stubs.StopConditions{Period: 2}, 2, 2, stubs.StopCycle},
+		{"block", "x = 2, y = 2\n2o$2o!", stubs.StopConditions{Period: 2}, 1, 1, stubs.StopCycle},
+		{"dot", "x = 1, y = 1\no!", stubs.StopConditions{Extinct: true}, 1, 0, stubs.StopExtinct},
+		{"glider", "x = 3, y = 3\nbo$2bo$3o!", stubs.StopConditions{Extinct: true}, 10, 0, ""},
+	} {
+		path := filepath.Join(dir, test.name+".rle")
+		if err := os.WriteFile(path, []byte(test.rle), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p := Params{Turns: 10, Threads: 2, ImageWidth: 16, ImageHeight: 16, Until: test.until, Pattern: path, OutDir: dir}
+		events := make(chan Event, 1000)
+		go Run(p, events, nil)
+		var final *FinalTurnComplete
+		for event := range events {
+			if e, ok := event.(FinalTurnComplete); ok {
+				final = &e
+			}
+		}
+		if final == nil {
+			t.Fatalf("%s: no FinalTurnComplete", test.name)
+		}
+		if final.CompletedTurns != test.turns || final.Reason != test.reason || final.Period != test.period {
+			t.Errorf("%s: expected to stop after %d turns for %q with period %d, got %d turns, %q, period %d",
+				test.name, test.turns, test.reason, test.period, final.CompletedTurns, final.Reason, final.Period)
+		}
+	}
+}
+
+// TestTurnComplete runs a glider for a thousand turns and checks that every turn
+// gets one TurnComplete, in order, even when the engine folds turn events.
+func TestTurnComplete(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "glider.rle")
+	if err := os.WriteFile(path, []byte("x = 3, y = 3\nbo$2bo$3o!"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const turns = 1000
+	events := make(chan Event, 1000)
+	go Run(Params{Turns: turns, Threads: 2, ImageWidth: 16, ImageHeight: 16, Pattern: path, OutDir: dir}, events, nil)
+	count := 0
+	for event := range events {
+		if e, ok := event.(TurnComplete); ok {
+			count++
+			if e.CompletedTurns != count {
+				t.Fatalf("TurnComplete number %d is for turn %d", count, e.CompletedTurns)
+			}
+		}
+	}
+	if count != turns {
+		t.Errorf("expected %d TurnComplete events, got %d", turns, count)
+	}
+}
@@ -3,8 +3,7 @@ package gol
 import (
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
+	"path/filepath"
 	"uk.ac.bris.cs/gameoflife/util"
 )
 
@@ -23,7 +22,7 @@ type ioState struct {
 	channels ioChannels
 }
 
-// ioCommand allows requesting behaviour from the io (pgm) goroutine.
+// ioCommand allows requesting behaviour from the io goroutine.
 type ioCommand uint8
 
 // This is a way of creating enums in Go.
@@ -37,93 +36,145 @@ const (
 	ioCheckIdle
 )
 
-// writePgmImage receives an array of bytes and writes it to a pgm file.
-func (io *ioState) writePgmImage() {
-	_ = os.Mkdir("out", os.ModePerm)
+// writeImage receives an array of bytes and writes it to a file in
+// Params.OutDir, in the format its extension names.
+func (io *ioState) writeImage() {
+	dir := io.params.outDir()
+	_ = os.MkdirAll(dir, os.ModePerm)
 
 	// Request a filename from the distributor.
 	filename := <-io.channels.filename
 
-	file, ioError := os.Create("out/" + filename + ".pgm")
-	util.Check(ioError)
-	defer file.Close()
-
-	_, _ = file.WriteString("P5\n")
-	//_, _ = file.WriteString("# PGM file writer by pnmmodules (https://github.com/owainkenwayucl/pnmmodules).\n")
-	_, _ = file.WriteString(strconv.Itoa(io.params.ImageWidth))
-	_, _ = file.WriteString(" ")
-	_, _ = file.WriteString(strconv.Itoa(io.params.ImageHeight))
-	_, _ = file.WriteString("\n")
-	_, _ = file.WriteString(strconv.Itoa(255))
-	_, _ = file.WriteString("\n")
-
-	world := make([][]byte, io.params.ImageHeight)
-	for i := range world {
-		world[i] = make([]byte, io.params.ImageWidth)
-	}
-
-	for y := 0; y < io.params.ImageHeight; y++ {
-		for x := 0; x < io.params.ImageWidth; x++ {
-			val := <-io.channels.output
-			//if val != 0 {
-			//	fmt.Println(x, y)
-			//}
-			world[y][x] = val
-		}
+	format, err := formatOf(filename, nil)
+	util.Check(err)
+	if format.write == nil {
+		panic("Cannot write " + format.name + " files")
 	}
 
-	for y := 0; y < io.params.ImageHeight; y++ {
-		for x := 0; x < io.params.ImageWidth; x++ {
-			_, ioError = file.Write([]byte{world[y][x]})
-			util.Check(ioError)
-		}
+	world := &pattern{width: io.params.ImageWidth, height: io.params.ImageHeight}
+	world.cells = make([]uint8, world.width*world.height)
+	for i := range world.cells {
+		world.cells[i] = <-io.channels.output
 	}
 
-	ioError = file.Sync()
+	file, ioError := os.Create(filepath.Join(dir, filename))
 	util.Check(ioError)
+	defer file.Close()
+
+	util.Check(format.write(file, world))
+	util.Check(file.Sync())
 
 	fmt.Println("File", filename, "output done!")
 }
 
-// readPgmImage opens a pgm file and sends its data as an array of bytes.
-func (io *ioState) readPgmImage() {
+// readImage opens the file the distributor names and sends its cells. Without
+// Params.Pattern the name is that of a pgm under images/ that must be exactly
+// the size of the world; otherwise it is the path of a pattern file, in a
+// format told by its extension or its first lines, placed in the world at
+// Params.PatternAt or in the middle of it. Cells at least Params.Threshold
+// bright are alive, which only matters for images.
+func (io *ioState) readImage() {
 
 	// Request a filename from the distributor.
 	filename := <-io.channels.filename
 
-	data, ioError := os.ReadFile("images/" + filename + ".pgm")
-	util.Check(ioError)
-
-	fields := strings.Fields(string(data))
-
-	if fields[0] != "P5" {
-		panic("Not a pgm file")
+	path := filename
+	if io.params.Pattern == "" {
+		path = "images/" + filename + ".pgm"
 	}
 
-	width, _ := strconv.Atoi(fields[1])
-	if width != io.params.ImageWidth {
-		panic("Incorrect width")
+	image, err := readPattern(path)
+	if err != nil {
+		panic(err)
 	}
-
-	height, _ := strconv.Atoi(fields[2])
-	if height != io.params.ImageHeight {
-		panic("Incorrect height")
+	threshold := io.params.Threshold
+	if threshold == 0 {
+		threshold = 128
 	}
-
-	maxval, _ := strconv.Atoi(fields[3])
-	if maxval != 255 {
-		panic("Incorrect maxval/bit depth")
+	for i, cell := range image.cells {
+		image.cells[i] = 0
+		if cell >= threshold {
+			image.cells[i] = 255
+		}
 	}
 
-	image := []byte(fields[4])
+	var world []uint8
+	if io.params.Pattern != "" {
+		x := (io.params.ImageWidth - image.width) / 2
+		y := (io.params.ImageHeight - image.height) / 2
+		if at := io.params.PatternAt; at != nil {
+			x, y = at.X, at.Y
+		}
+		world, err = image.place(io.params.ImageWidth, io.params.ImageHeight, x, y)
+		if err != nil {
+			panic(fmt.Sprintf("%s: %v", path, err))
+		}
+	} else {
+		if image.width != io.params.ImageWidth {
+			panic("Incorrect width")
+		}
+		if image.height != io.params.ImageHeight {
+			panic("Incorrect height")
+		}
+		world = image.cells
+	}
 
-	for _, b := range image {
+	for _, b := range world {
 		io.channels.input <- b
 	}
 
 	fmt.Println("File", filename, "input done!")
 }
 
+// readPattern reads the pattern file at path, in a format told by its extension
+// or its first lines.
+func readPattern(path string) (*pattern, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format, err := formatOf(path, data)
+	if err != nil {
+		return nil, err
+	}
+	image, err := format.read(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return image, nil
+}
+
+// ReadSize returns the size of the pattern file at path, so that a world can
+// be made to fit it exactly. Formats with a header are not decoded any further.
+func ReadSize(path string) (width, height int, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	format, err := formatOf(path, data)
+	if err != nil {
+		return 0, 0, err
+	}
+	if format.size != nil {
+		width, height, err = format.size(data)
+	} else {
+		var image *pattern
+		if image, err = format.read(data); err == nil {
+			width, height = image.width, image.height
+		}
+	}
+	if err == nil {
+		err = checkSize(width, height)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %v", path, err)
+	}
+	if width == 0 || height == 0 {
+		return 0, 0, fmt.Errorf("%s: pattern is empty", path)
+	}
+	return width, height, nil
+}
+
 // startIo should be the entrypoint of the io goroutine.
 func startIo(p Params, c ioChannels) {
 	io := ioState{
@@ -135,9 +186,9 @@ func startIo(p Params, c ioChannels) {
 		// Block and wait for requests from the distributor
 		switch command {
 		case ioInput:
-			io.readPgmImage()
+			io.readImage()
 		case ioOutput:
-			io.writePgmImage()
+			io.writeImage()
 		case ioCheckIdle:
 			io.channels.idle <- true
 		}
@@ -0,0 +1,369 @@
+package gol
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// localEngine evolves the world in this process, splitting every turn across
+// Params.Threads goroutines. It mirrors the broker's state machine and events so
+// that the distributor sees the same behaviour from either backend. run numbers
+// the runs Start began.
+type localEngine struct {
+	mu         sync.Mutex
+	resumed    *sync.Cond
+	world      []uint8
+	next       []uint8
+	width      int
+	height     int
+	turn       int
+	params     Params
+	processing bool
+	paused     bool
+	steps      int
+	speed      float64
+	lastTurnAt time.Time
+	until      *stubs.StopWatch
+	stopReason string
+	stop       bool
+	finished   chan struct{}
+	run        int
+	events     stubs.EventLog
+}
+
+func newLocalEngine() *localEngine {
+	e := &localEngine{}
+	e.resumed = sync.NewCond(&e.mu)
+	return e
+}
+
+func (e *localEngine) Start(p Params, world [][]uint8) error {
+	if err := p.Until.Validate(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	if e.processing {
+		// Previous simulation is running; stop it and wait for it to finish
+		e.stop = true
+		e.paused = false
+		e.resumed.Broadcast()
+		finished := e.finished
+		e.mu.Unlock()
+		<-finished
+		e.mu.Lock()
+	}
+	e.width = p.ImageWidth
+	e.height = p.ImageHeight
+	e.world = make([]uint8, e.width*e.height)
+	e.next = make([]uint8, e.width*e.height)
+	for y := 0; y < e.height; y++ {
+		copy(e.world[y*e.width:(y+1)*e.width], world[y])
+	}
+	e.turn = 0
+	e.params = p
+	e.until = stubs.NewStopWatch(p.Until, e.world)
+	e.stopReason = ""
+	e.processing = true
+	e.paused = false
+	e.steps = 0
+	e.speed = 0
+	e.stop = false
+	e.finished = make(chan struct{})
+	e.run++
+	e.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: e.run, State: stubs.StateExecuting})
+	go e.evolve(e.run, e.finished)
+	go e.reportAlive(e.run, e.finished)
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *localEngine) evolve(run int, finished chan struct{}) {
+	defer close(finished)
+	e.mu.Lock()
+	remaining, timed := e.until.Remaining()
+	e.mu.Unlock()
+	if timed {
+		timer := time.AfterFunc(remaining, func() { e.timeOut(run) })
+		defer timer.Stop()
+	}
+	for t := 0; t < e.params.Turns; t++ {
+		e.mu.Lock()
+		for !e.stop {
+			if e.paused && e.steps == 0 {
+				e.resumed.Wait()
+				continue
+			}
+			// Hold the turn back to keep to the target speed.
+			wait := e.untilNextTurn()
+			if wait <= 0 {
+				break
+			}
+			if wait > 100*time.Millisecond {
+				wait = 100 * time.Millisecond
+			}
+			e.mu.Unlock()
+			time.Sleep(wait)
+			e.mu.Lock()
+		}
+		if e.stop {
+			e.mu.Unlock()
+			break
+		}
+		e.mu.Unlock()
+
+		e.step()
+
+		e.mu.Lock()
+		e.world, e.next = e.next, e.world
+		e.turn = t + 1
+		e.lastTurnAt = time.Now()
+		e.events.Add(stubs.RunEvent{Kind: stubs.EventTurn, Run: run, CompletedTurns: e.turn})
+		alive := -1
+		if e.until.NeedsAlive() {
+			alive = e.aliveCount()
+		}
+		if reason, period := e.until.Check(e.world, alive); reason != "" {
+			e.mu.Unlock()
+			e.finish(run, reason, period)
+			return
+		}
+		if e.steps > 0 {
+			e.steps--
+			if e.steps == 0 {
+				e.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: run, CompletedTurns: e.turn, State: stubs.StatePaused})
+			}
+		}
+		e.mu.Unlock()
+	}
+
+	e.mu.Lock()
+	reason := e.stopReason
+	e.mu.Unlock()
+	e.finish(run, reason, 0)
+}
+
+// timeOut stops run once its Timeout has passed, waking it if it is paused.
+func (e *localEngine) timeOut(run int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.run != run || !e.processing || e.stop {
+		return
+	}
+	e.stop = true
+	e.stopReason = stubs.StopTimeout
+	e.resumed.Broadcast()
+}
+
+// finish ends run, because its stop condition reason held if that is set.
+func (e *localEngine) finish(run int, reason string, period int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.processing = false
+	e.events.Add(stubs.RunEvent{
+		Kind:           stubs.EventFinished,
+		Run:            run,
+		CompletedTurns: e.turn,
+		CellsCount:     e.aliveCount(),
+		Reason:         reason,
+		Period:         period,
+	})
+}
+
+// untilNextTurn returns how long the run must wait for its next turn to keep
+// to its target speed. The caller must hold e.mu.
+func (e *localEngine) untilNextTurn() time.Duration {
+	if e.speed <= 0 {
+		return 0
+	}
+	return time.Until(e.lastTurnAt.Add(time.Duration(float64(time.Second) / e.speed)))
+}
+
+// reportAlive logs the number of alive cells of run every stubs.AliveInterval
+// until finished is closed.
+func (e *localEngine) reportAlive(run int, finished <-chan struct{}) {
+	ticker := time.NewTicker(stubs.AliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-finished:
+			return
+		case <-ticker.C:
+			e.mu.Lock()
+			e.events.Add(stubs.RunEvent{Kind: stubs.EventAlive, Run: run, CompletedTurns: e.turn, CellsCount: e.aliveCount()})
+			e.mu.Unlock()
+		}
+	}
+}
+
+// step computes the next generation into e.next, one band of rows per thread.
+// Only the run goroutine writes e.world, so it is safe to read without the lock.
+func (e *localEngine) step() {
+	threads := e.params.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	if threads > e.height {
+		threads = e.height
+	}
+	var wg sync.WaitGroup
+	wg.Add(threads)
+	for i := 0; i < threads; i++ {
+		go func(startY, endY int) {
+			defer wg.Done()
+			e.calculateRows(startY, endY)
+		}(i*e.height/threads, (i+1)*e.height/threads)
+	}
+	wg.Wait()
+}
+
+func (e *localEngine) calculateRows(startY, endY int) {
+	stochastic := e.params.Probability > 0 && e.params.Probability < 1
+	for y := startY; y < endY; y++ {
+		up := (y - 1 + e.height) % e.height * e.width
+		row := y * e.width
+		down := (y + 1) % e.height * e.width
+		for x := 0; x < e.width; x++ {
+			left := (x - 1 + e.width) % e.width
+			right := (x + 1) % e.width
+			neighbours := 0
+			for _, i := range [8]int{up + left, up + x, up + right, row + left, row + right, down + left, down + x, down + right} {
+				if e.world[i] == 255 {
+					neighbours++
+				}
+			}
+			cell := e.world[row+x]
+			next := uint8(0)
+			if neighbours == 3 || (neighbours == 2 && cell == 255) {
+				next = 255
+			}
+			if next != cell && stochastic && util.CellRandom(e.params.Seed, e.turn, x, y) >= e.params.Probability {
+				next = cell
+			}
+			e.next[row+x] = next
+		}
+	}
+}
+
+func (e *localEngine) Pause() (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.processing || (e.paused && e.steps == 0) {
+		return 0, nil
+	}
+	e.paused = true
+	e.steps = 0
+	e.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: e.run, CompletedTurns: e.turn, State: stubs.StatePaused})
+	return e.turn, nil
+}
+
+func (e *localEngine) Resume() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.processing || !e.paused {
+		return nil
+	}
+	e.paused = false
+	e.resumed.Broadcast()
+	if e.steps == 0 {
+		e.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: e.run, CompletedTurns: e.turn, State: stubs.StateExecuting})
+	}
+	e.steps = 0
+	return nil
+}
+
+func (e *localEngine) Step(n int) (int, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("cannot step %d turns", n)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.processing || !e.paused {
+		return 0, errors.New("only a paused run can be stepped")
+	}
+	if e.steps == 0 {
+		e.events.Add(stubs.RunEvent{Kind: stubs.EventState, Run: e.run, CompletedTurns: e.turn, State: stubs.StateExecuting})
+	}
+	e.steps += n
+	e.resumed.Broadcast()
+	turn := e.turn + e.steps
+	if turn > e.params.Turns {
+		turn = e.params.Turns
+	}
+	return turn, nil
+}
+
+func (e *localEngine) SetSpeed(turnsPerSecond float64) error {
+	if turnsPerSecond != 0 && !(turnsPerSecond >= stubs.MinSpeed && turnsPerSecond <= math.MaxFloat64) {
+		return fmt.Errorf("speed must be 0 for unlimited or at least %v turns per second, not %v", stubs.MinSpeed, turnsPerSecond)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.speed = turnsPerSecond
+	e.events.Add(stubs.RunEvent{Kind: stubs.EventSpeed, Run: e.run, CompletedTurns: e.turn, TurnsPerSecond: e.speed})
+	return nil
+}
+
+func (e *localEngine) Snapshot() ([][]uint8, int, bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	world := make([][]uint8, e.height)
+	for y := range world {
+		world[y] = make([]uint8, e.width)
+		copy(world[y], e.world[y*e.width:(y+1)*e.width])
+	}
+	return world, e.turn, e.processing, nil
+}
+
+func (e *localEngine) AliveCount() (int, int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.aliveCount(), e.turn, nil
+}
+
+// aliveCount counts the alive cells of the current generation. The caller must
+// hold e.mu.
+func (e *localEngine) aliveCount() int {
+	count := 0
+	for _, cell := range e.world {
+		if cell == 255 {
+			count++
+		}
+	}
+	return count
+}
+
+func (e *localEngine) Subscribe(after uint64, wait time.Duration) ([]stubs.RunEvent, error) {
+	e.mu.Lock()
+	run := e.run
+	e.mu.Unlock()
+	response := new(stubs.SubscribeResponse)
+	e.events.Wait(&stubs.SubscribeRequest{Run: run, After: after, Wait: wait}, response)
+	return response.Events, nil
+}
+
+func (e *localEngine) Stop() error {
+	e.mu.Lock()
+	e.stop = true
+	e.paused = false
+	e.resumed.Broadcast()
+	finished := e.finished
+	e.mu.Unlock()
+	if finished != nil {
+		<-finished
+	}
+	return nil
+}
+
+func (e *localEngine) Shutdown() error {
+	return e.Stop()
+}
+
+func (e *localEngine) Close() error {
+	return e.Stop()
+}
@@ -0,0 +1,216 @@
+package gol
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// newWorld returns a 16x16 world with cells alive.
+func newWorld(cells ...util.Cell) [][]uint8 {
+	world := make([][]uint8, 16)
+	for y := range world {
+		world[y] = make([]uint8, 16)
+	}
+	for _, c := range cells {
+		world[c.Y][c.X] = 255
+	}
+	return world
+}
+
+var (
+	glider = []util.Cell{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}}
+	block  = []util.Cell{{X: 4, Y: 4}, {X: 5, Y: 4}, {X: 4, Y: 5}, {X: 5, Y: 5}}
+)
+
+// startLocal starts a local run of turns turns on world, stopping it when the
+// test ends.
+func startLocal(t *testing.T, world [][]uint8, turns int, until stubs.StopConditions) *localEngine {
+	e := newLocalEngine()
+	p := Params{Turns: turns, Threads: 2, ImageWidth: 16, ImageHeight: 16, Until: until}
+	if err := e.Start(p, world); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { e.Stop() })
+	return e
+}
+
+// waitFinished returns the EventFinished of e's run, failing the test if it
+// does not come within five seconds.
+func waitFinished(t *testing.T, e *localEngine) stubs.RunEvent {
+	after := uint64(0)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		events, err := e.Subscribe(after, time.Until(deadline))
+		if err != nil || len(events) == 0 || time.Now().After(deadline) {
+			t.Fatalf("the run did not finish: %v", err)
+		}
+		for _, event := range events {
+			if event.Kind == stubs.EventFinished {
+				return event
+			}
+			after = event.Seq
+		}
+	}
+}
+
+// TestLocalStep checks that stepping a paused local run completes exactly the
+// turns asked for and reports the run executing and then paused again.
+func TestLocalStep(t *testing.T) {
+	e := startLocal(t, newWorld(glider...), 1000000, stubs.StopConditions{})
+	if _, err := e.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	// Let a turn already in flight finish before counting.
+	time.Sleep(50 * time.Millisecond)
+	_, paused, _, _ := e.Snapshot()
+	events, _ := e.Subscribe(0, 0)
+	after := events[len(events)-1].Seq
+
+	turn, err := e.Step(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if turn != paused+3 {
+		t.Errorf("expected to pause at turn %d, got %d", paused+3, turn)
+	}
+	var states []stubs.RunEvent
+	for len(states) < 2 {
+		events, err := e.Subscribe(after, 5*time.Second)
+		if err != nil || len(events) == 0 {
+			t.Fatalf("no state events after %+v: %v", states, err)
+		}
+		for _, event := range events {
+			if event.Kind == stubs.EventState {
+				states = append(states, event)
+			}
+			after = event.Seq
+		}
+	}
+	if states[0].State != stubs.StateExecuting || states[1].State != stubs.StatePaused || states[1].CompletedTurns != turn {
+		t.Errorf("expected executing and then paused at turn %d, got %+v", turn, states)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, now, _, _ := e.Snapshot(); now != turn {
+		t.Errorf("run moved on to turn %d after stepping to %d", now, turn)
+	}
+
+	if _, err := e.Step(0); err == nil {
+		t.Error("expected stepping no turns to fail")
+	}
+	if err := e.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Step(1); err == nil {
+		t.Error("expected stepping a running run to fail")
+	}
+}
+
+// TestLocalSetSpeed checks that a local run held to a target speed completes
+// no more turns than that rate allows in the time that actually passed, fewer
+// than the same run unthrottled, and that an unusable speed is refused.
+func TestLocalSetSpeed(t *testing.T) {
+	e := startLocal(t, newWorld(glider...), 1000000, stubs.StopConditions{})
+	// count returns the turns completed in about half a second at speed, and
+	// how long it really took.
+	count := func(speed float64) (int, time.Duration) {
+		if err := e.SetSpeed(speed); err != nil {
+			t.Fatal(err)
+		}
+		// Let a turn already in flight finish before counting.
+		time.Sleep(100 * time.Millisecond)
+		_, start, _, _ := e.Snapshot()
+		started := time.Now()
+		time.Sleep(500 * time.Millisecond)
+		_, end, _, _ := e.Snapshot()
+		return end - start, time.Since(started)
+	}
+
+	throttled, elapsed := count(20)
+	// Turns are at least 1/20s apart, so elapsed allows one more than 20 a second.
+	if limit := int(elapsed.Seconds()*20) + 1; throttled < 1 || throttled > limit {
+		t.Errorf("expected 1 to %d turns in %v at 20 turns a second, got %d", limit, elapsed, throttled)
+	}
+	if unthrottled, _ := count(0); unthrottled <= throttled {
+		t.Errorf("expected more than the %d throttled turns without a target speed, got %d", throttled, unthrottled)
+	}
+	for _, speed := range []float64{-1, stubs.MinSpeed / 2, math.Inf(1), math.NaN()} {
+		if err := e.SetSpeed(speed); err == nil {
+			t.Errorf("expected a speed of %v to be refused", speed)
+		}
+	}
+}
+
+// TestLocalStop checks that Stop ends a local run at once, even a paused one,
+// and that the run reports finishing early.
+func TestLocalStop(t *testing.T) {
+	for _, pause := range []bool{false, true} {
+		e := startLocal(t, newWorld(glider...), 1000000, stubs.StopConditions{})
+		if pause {
+			if _, err := e.Pause(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := e.Stop(); err != nil {
+			t.Fatal(err)
+		}
+		_, turn, processing, _ := e.Snapshot()
+		if processing {
+			t.Errorf("paused %v: run still processing after Stop", pause)
+		}
+		if finished := waitFinished(t, e); finished.CompletedTurns != turn || finished.Reason != "" {
+			t.Errorf("paused %v: expected to finish at turn %d without a reason, got %+v", pause, turn, finished)
+		}
+	}
+}
+
+// TestLocalStopConditions checks that a local run ends as soon as a stop
+// condition holds and reports why. A block is a still life.
+func TestLocalStopConditions(t *testing.T) {
+	alive := len(block)
+	for _, test := range []struct {
+		until  stubs.StopConditions
+		reason string
+		turn   int
+	}{
+		{stubs.StopConditions{Period: 1}, stubs.StopCycle, 1},
+		{stubs.StopConditions{Above: alive - 1}, stubs.StopAbove, 1},
+		{stubs.StopConditions{Below: alive + 1, Period: 1}, stubs.StopBelow, 1},
+		{stubs.StopConditions{Below: alive}, "", 20},
+	} {
+		e := startLocal(t, newWorld(block...), 20, test.until)
+		finished := waitFinished(t, e)
+		if finished.Reason != test.reason || finished.CompletedTurns != test.turn || finished.CellsCount != alive {
+			t.Errorf("%+v: expected to finish after %d turns because %q, got %+v", test.until, test.turn, test.reason, finished)
+		}
+	}
+	if err := newLocalEngine().Start(Params{Turns: 1, ImageWidth: 16, ImageHeight: 16, Until: stubs.StopConditions{Above: -1}}, newWorld()); err == nil {
+		t.Error("expected a negative condition to be refused")
+	}
+}
+
+// TestLocalStopTimeout checks that a local run's Timeout stops it on time while
+// it is paused or held back to a low speed, not only once its next turn
+// completes.
+func TestLocalStopTimeout(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+	for _, wait := range []string{"paused", "throttled"} {
+		started := time.Now()
+		e := startLocal(t, newWorld(glider...), 1000000, stubs.StopConditions{Timeout: timeout})
+		if wait == "paused" {
+			_, _ = e.Pause()
+		} else {
+			_ = e.SetSpeed(stubs.MinSpeed)
+		}
+		finished := waitFinished(t, e)
+		if elapsed := time.Since(started); elapsed < timeout || elapsed > 5*time.Second {
+			t.Errorf("%s: the run stopped after %v, expected about %v", wait, elapsed, timeout)
+		}
+		if finished.Reason != stubs.StopTimeout {
+			t.Errorf("%s: expected to finish because of the timeout, got %+v", wait, finished)
+		}
+	}
+}
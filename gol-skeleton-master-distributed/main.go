@@ -7,9 +7,11 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"uk.ac.bris.cs/gameoflife/gol"
 	"uk.ac.bris.cs/gameoflife/sdl"
+	"uk.ac.bris.cs/gameoflife/util"
 )
 
 // main is the function called when starting Game of Life with 'go run .'
@@ -41,12 +43,160 @@ func main() {
 		10000000000,
 		"Specify the number of turns to process. Defaults to 10000000000.")
 
+	flag.Uint64Var(
+		&params.Seed,
+		"seed",
+		0,
+		"Specify the random seed for noisy Life. Defaults to 0.")
+
+	flag.Float64Var(
+		&params.Probability,
+		"prob",
+		1,
+		"Specify the probability that each transition happens. Defaults to 1 (ordinary Life).")
+
+	flag.StringVar(
+		&params.Broker,
+		"broker",
+		"",
+		"Specify the broker address, e.g. 3.84.187.222:8030. Defaults to computing locally.")
+
+	flag.BoolVar(
+		&params.Compress,
+		"compress",
+		true,
+		"Specify whether to send worlds to and from the broker compressed, if it agrees. Defaults to true.")
+
+	flag.StringVar(
+		&params.TLSCert,
+		"tlscert",
+		"",
+		"Specify the PEM certificate to connect to the broker over TLS with. Defaults to plain TCP.")
+
+	flag.StringVar(
+		&params.TLSKey,
+		"tlskey",
+		"",
+		"Specify the PEM key of the TLS certificate.")
+
+	flag.StringVar(
+		&params.TLSCA,
+		"tlsca",
+		"",
+		"Specify the PEM CA certificate the broker's certificate must be signed by.")
+
+	flag.StringVar(
+		&params.Token,
+		"token",
+		"",
+		"Specify the token to send to a broker that checks tokens. Needs the controller role.")
+
+	flag.DurationVar(
+		&params.Timeout,
+		"timeout",
+		30*time.Second,
+		"Specify how long to wait for the broker to answer a call. 0 waits for ever. Defaults to 30s.")
+
+	flag.BoolVar(
+		&params.Until.Extinct,
+		"until-extinct",
+		false,
+		"Stop once no cells are alive.")
+
+	flag.IntVar(
+		&params.Until.Above,
+		"until-above",
+		0,
+		"Stop once more than this many cells are alive. Defaults to 0 (never).")
+
+	flag.IntVar(
+		&params.Until.Below,
+		"until-below",
+		0,
+		"Stop once fewer than this many cells are alive. Defaults to 0 (never).")
+
+	flag.IntVar(
+		&params.Until.Period,
+		"until-period",
+		0,
+		"Stop once the world repeats within this many turns; 1 catches still lifes. Defaults to 0 (never).")
+
+	flag.DurationVar(
+		&params.Until.Timeout,
+		"until-time",
+		0,
+		"Stop once the run has gone on this long, e.g. 5m. Defaults to 0 (never).")
+
+	flag.StringVar(
+		&params.Pattern,
+		"pattern",
+		"",
+		"Start from this pattern file (.pgm, .rle, .cells, Life 1.06 .lif, .png, .gif or .jpg) placed in a world of -w by -h cells instead of images/WxH.pgm.")
+
+	input := flag.String(
+		"input",
+		"",
+		"Start from this file in any -pattern format, taking the size of the world from it instead of -w and -h.")
+
+	flag.Func(
+		"at",
+		"Place the -pattern with its top left cell at x,y. Defaults to the middle of the world.",
+		func(value string) error {
+			var at util.Cell
+			if _, err := fmt.Sscanf(value, "%d,%d", &at.X, &at.Y); err != nil {
+				return fmt.Errorf("want x,y: %v", err)
+			}
+			params.PatternAt = &at
+			return nil
+		})
+
+	flag.StringVar(
+		&params.OutputFormat,
+		"format",
+		"pgm",
+		"Format of the files written to -outdir: pgm, rle, cells, lif or png.")
+
+	flag.StringVar(
+		&params.OutDir,
+		"outdir",
+		"out",
+		"Directory to write output files to.")
+
+	threshold := flag.Uint(
+		"threshold",
+		128,
+		"Grey level from 1 to 255 from which a pixel of a -pattern image is alive.")
+
 	headless := flag.Bool(
 		"headless",
 		false,
 		"Disable the SDL window for running in a headless environment.")
 
 	flag.Parse()
+	if *threshold < 1 || *threshold > 255 {
+		fmt.Fprintln(os.Stderr, "-threshold must be from 1 to 255")
+		os.Exit(2)
+	}
+	params.Threshold = uint8(*threshold)
+	if *input != "" {
+		if params.Pattern != "" {
+			fmt.Fprintln(os.Stderr, "-input and -pattern cannot both be given")
+			os.Exit(2)
+		}
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "w" || f.Name == "h" {
+				fmt.Fprintf(os.Stderr, "-input and -%s cannot both be given\n", f.Name)
+				os.Exit(2)
+			}
+		})
+		width, height, err := gol.ReadSize(*input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		params.ImageWidth, params.ImageHeight = width, height
+		params.Pattern = *input
+	}
 
 	fmt.Printf("%-10v %v\n", "Threads", params.Threads)
 	fmt.Printf("%-10v %v\n", "Width", params.ImageWidth)
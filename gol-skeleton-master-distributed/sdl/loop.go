@@ -16,6 +16,7 @@ func Run(p gol.Params, events <-chan gol.Event, keyPresses chan<- rune) {
 	dirty := false
 	refreshTicker := time.NewTicker(time.Second / time.Duration(FPS))
 	avgTurns := util.NewAvgTurns()
+	target := 0.0
 
 sdl:
 	for {
@@ -38,6 +39,22 @@ sdl:
 						keyPresses <- 'q'
 					case sdl.K_k:
 						keyPresses <- 'k'
+					case sdl.K_n:
+						// Only on key down, so that releasing n does not step
+						// again. Shift steps 100 turns.
+						if e.Type == sdl.KEYDOWN && e.Keysym.Mod&sdl.KMOD_SHIFT != 0 {
+							keyPresses <- 'N'
+						} else if e.Type == sdl.KEYDOWN {
+							keyPresses <- 'n'
+						}
+					case sdl.K_PLUS, sdl.K_EQUALS, sdl.K_KP_PLUS:
+						if e.Type == sdl.KEYDOWN {
+							keyPresses <- '+'
+						}
+					case sdl.K_MINUS, sdl.K_KP_MINUS:
+						if e.Type == sdl.KEYDOWN {
+							keyPresses <- '-'
+						}
 					}
 				}
 			}
@@ -60,9 +77,12 @@ sdl:
 			case gol.TurnComplete:
 				dirty = true
 			case gol.AliveCellsCount:
-				fmt.Printf("Completed Turns %-8v %-20v Avg%+5v turns/sec\n", event.GetCompletedTurns(), event, avgTurns.Get(event.GetCompletedTurns()))
+				fmt.Printf("Completed Turns %-8v %-20v Avg%+5v turns/sec%v\n", event.GetCompletedTurns(), event, avgTurns.Get(event.GetCompletedTurns()), targetSuffix(target))
 			case gol.FinalTurnComplete:
 				fmt.Printf("Completed Turns %-8v %v\n", event.GetCompletedTurns(), event)
+			case gol.SpeedChange:
+				target = e.TurnsPerSecond
+				fmt.Printf("Completed Turns %-8v %v\n", event.GetCompletedTurns(), event)
 			case gol.ImageOutputComplete:
 				fmt.Printf("Completed Turns %-8v %v\n", event.GetCompletedTurns(), event)
 			case gol.StateChange:
@@ -77,12 +97,16 @@ sdl:
 
 func RunHeadless(events <-chan gol.Event) {
 	avgTurns := util.NewAvgTurns()
+	target := 0.0
 	for event := range events {
 		switch e := event.(type) {
 		case gol.AliveCellsCount:
-			fmt.Printf("Completed Turns %-8v %-20v Avg%+5v turns/sec\n", event.GetCompletedTurns(), event, avgTurns.Get(event.GetCompletedTurns()))
+			fmt.Printf("Completed Turns %-8v %-20v Avg%+5v turns/sec%v\n", event.GetCompletedTurns(), event, avgTurns.Get(event.GetCompletedTurns()), targetSuffix(target))
 		case gol.FinalTurnComplete:
 			fmt.Printf("Completed Turns %-8v %v\n", event.GetCompletedTurns(), "Final Turn Complete")
+		case gol.SpeedChange:
+			target = e.TurnsPerSecond
+			fmt.Printf("Completed Turns %-8v %v\n", event.GetCompletedTurns(), event)
 		case gol.ImageOutputComplete:
 			fmt.Printf("Completed Turns %-8v %v\n", event.GetCompletedTurns(), event)
 		case gol.StateChange:
@@ -93,3 +117,11 @@ func RunHeadless(events <-chan gol.Event) {
 		}
 	}
 }
+
+// targetSuffix describes the target speed for the turns/sec report, if one is set.
+func targetSuffix(turnsPerSecond float64) string {
+	if turnsPerSecond == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (target %v)", turnsPerSecond)
+}
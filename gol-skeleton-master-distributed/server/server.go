@@ -6,82 +6,57 @@ import (
 	"log"
 	"net"
 	"net/rpc"
-	"sync"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"uk.ac.bris.cs/gameoflife/stubs"
+	"uk.ac.bris.cs/gameoflife/worker"
 )
 
-type GolWorker struct {
-	mu sync.Mutex
-}
-
-func mod(a, b int) int {
-	return (a%b + b) % b
-}
+func main() {
+	pAddr := flag.String("port", "8031", "Port to listen on")
+	pGRPC := flag.String("grpc", "", "Port to serve gRPC on, empty to disable")
+	var tlsFiles stubs.TLSFiles
+	flag.StringVar(&tlsFiles.Cert, "tlscert", "", "PEM certificate to serve TLS with, empty for plain TCP")
+	flag.StringVar(&tlsFiles.Key, "tlskey", "", "PEM key of the TLS certificate")
+	flag.StringVar(&tlsFiles.CA, "tlsca", "", "PEM CA certificate that brokers must present certificates from")
+	flag.Parse()
 
-func calculateNeighbours(world [][]uint8, x, y, width, height int) int {
-	count := 0
-	for deltaY := -1; deltaY <= 1; deltaY++ {
-		for deltaX := -1; deltaX <= 1; deltaX++ {
-			if deltaX == 0 && deltaY == 0 {
-				continue
-			}
-			nx := mod(x+deltaX, width)
-			ny := mod(y+deltaY, height)
-			if world[ny][nx] == 255 {
-				count++
-			}
-		}
+	tlsConfig, err := tlsFiles.ServerConfig()
+	if err != nil {
+		log.Fatal("Error loading TLS certificates:", err)
 	}
-	return count
-}
-
-func (g *GolWorker) CalculateNextState(req *stubs.WorkerRequest, res *stubs.WorkerResponse) error {
-	g.mu.Lock()
-	defer g.mu.Unlock()
 
-	worldSlice := req.WorldSlice
-	height := len(worldSlice)
-	width := req.ImageWidth
+	golWorker := new(worker.GolWorker)
+	server := rpc.NewServer()
+	server.RegisterName("GolWorker", golWorker) // 워커로 등록
 
-	newWorldSlice := make([][]uint8, height-2)
-	for y := 1; y < height-1; y++ {
-		newRow := make([]uint8, width)
-		for x := 0; x < width; x++ {
-			neighbours := calculateNeighbours(worldSlice, x, y, width, height)
-			if worldSlice[y][x] == 255 {
-				if neighbours == 2 || neighbours == 3 {
-					newRow[x] = 255
-				} else {
-					newRow[x] = 0
-				}
-			} else {
-				if neighbours == 3 {
-					newRow[x] = 255
-				} else {
-					newRow[x] = 0
-				}
-			}
+	if *pGRPC != "" {
+		var opts []grpc.ServerOption
+		if tlsConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
 		}
-		newWorldSlice[y-1] = newRow
+		grpcServer := stubs.NewGRPCServer(opts...)
+		stubs.RegisterWorker(grpcServer, golWorker)
+		grpcListener, err := net.Listen("tcp", ":"+*pGRPC)
+		if err != nil {
+			log.Fatal("Error starting Gol worker:", err)
+		}
+		fmt.Println("Gol Worker serving gRPC on port", *pGRPC)
+		go grpcServer.Serve(grpcListener)
 	}
 
-	res.WorldSlice = newWorldSlice
-	return nil
-}
-
-func main() {
-	pAddr := flag.String("port", "8031", "Port to listen on")
-	flag.Parse()
-
-	golWorker := new(GolWorker)
-	rpc.RegisterName("GolWorker", golWorker) // 워커로 등록
-
-	listener, err := net.Listen("tcp", ":"+*pAddr)
+	listener, err := stubs.Listen(":"+*pAddr, tlsConfig)
 	if err != nil {
 		log.Fatal("Error starting Gol worker:", err)
 	}
 	defer listener.Close()
 	fmt.Println("Gol Worker listening on port", *pAddr)
-	rpc.Accept(listener)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Fatal("Error accepting connection:", err)
+		}
+		go server.ServeCodec(worker.NewServerCodec(conn, golWorker))
+	}
 }
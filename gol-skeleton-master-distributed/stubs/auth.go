@@ -0,0 +1,66 @@
+package stubs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// A broker can require every request to carry a token. Each token grants a
+// role: observers may watch a run, controllers may also start, pause, resume,
+// stop and shut it down.
+const (
+	RoleObserver   = "observer"
+	RoleController = "controller"
+)
+
+// roleRank orders the roles, each allowed everything the ones before it are.
+var roleRank = map[string]int{RoleObserver: 1, RoleController: 2}
+
+// Tokens maps each token a broker accepts to its role. A nil Tokens lets
+// anyone do anything.
+type Tokens map[string]string
+
+// LoadTokens reads a file of "<role> <token>" lines. Blank lines and lines
+// starting with # are skipped.
+func LoadTokens(path string) (Tokens, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	tokens := make(Tokens)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 || roleRank[fields[0]] == 0 {
+			return nil, fmt.Errorf("%s:%d: expected \"observer <token>\" or \"controller <token>\"", path, line)
+		}
+		tokens[fields[1]] = fields[0]
+	}
+	return tokens, scanner.Err()
+}
+
+// Authorize returns the role of token and an error if it does not allow
+// method, which needs role. The role is empty if token is missing or unknown.
+func (t Tokens) Authorize(token, method, role string) (string, error) {
+	if t == nil {
+		return RoleController, nil
+	}
+	if token == "" {
+		return "", fmt.Errorf("unauthorized: %s needs a token", method)
+	}
+	granted, ok := t[token]
+	if !ok {
+		return "", fmt.Errorf("unauthorized: unknown token for %s", method)
+	}
+	if roleRank[granted] < roleRank[role] {
+		return granted, fmt.Errorf("permission denied: %s needs a %s token, not an %s one", method, role, granted)
+	}
+	return granted, nil
+}
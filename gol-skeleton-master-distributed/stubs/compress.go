@@ -0,0 +1,196 @@
+package stubs
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Worlds are mostly long runs of dead cells, so the cells of a world or block
+// can be sent encoded. Peers agree on an encoding in their Hello call.
+const (
+	// EncodingRaw sends one byte per cell.
+	EncodingRaw = ""
+	// EncodingRLE sends the lengths of alternating runs of dead and alive
+	// cells, starting with dead, as uvarints. Any non-zero cell is alive.
+	EncodingRLE = "rle"
+	// EncodingFlate sends the raw cells through DEFLATE.
+	EncodingFlate = "flate"
+)
+
+// Encodings lists the encodings this build understands, preferred first.
+var Encodings = []string{EncodingRLE, EncodingFlate}
+
+// ChooseEncoding returns the first of offered that is also in supported, or
+// EncodingRaw if there is none.
+func ChooseEncoding(offered, supported []string) string {
+	for _, enc := range offered {
+		for _, s := range supported {
+			if enc == s {
+				return enc
+			}
+		}
+	}
+	return EncodingRaw
+}
+
+// encodingName returns the constant for a known encoding name, so that decoding
+// a frame does not allocate a string for it.
+func encodingName(b []byte) (string, error) {
+	switch string(b) {
+	case EncodingRaw:
+		return EncodingRaw, nil
+	case EncodingRLE:
+		return EncodingRLE, nil
+	case EncodingFlate:
+		return EncodingFlate, nil
+	}
+	return "", fmt.Errorf("stubs: unknown encoding %q", b)
+}
+
+// Compressor encodes and decodes cells, keeping its DEFLATE state between
+// calls. The zero value is ready to use; a Compressor is not safe for
+// concurrent use.
+type Compressor struct {
+	flateWriter *flate.Writer
+	flateReader io.ReadCloser
+	out         bytes.Buffer
+	in          bytes.Reader
+}
+
+// Encode appends cells encoded with enc to dst.
+func (c *Compressor) Encode(dst []byte, enc string, cells []uint8) ([]byte, error) {
+	switch enc {
+	case EncodingRaw:
+		return append(dst, cells...), nil
+	case EncodingRLE:
+		return appendRLE(dst, cells), nil
+	case EncodingFlate:
+		c.out.Reset()
+		if c.flateWriter == nil {
+			c.flateWriter, _ = flate.NewWriter(&c.out, flate.BestSpeed)
+		} else {
+			c.flateWriter.Reset(&c.out)
+		}
+		if _, err := c.flateWriter.Write(cells); err != nil {
+			return dst, err
+		}
+		if err := c.flateWriter.Close(); err != nil {
+			return dst, err
+		}
+		return append(dst, c.out.Bytes()...), nil
+	}
+	return dst, fmt.Errorf("stubs: unknown encoding %q", enc)
+}
+
+// Decode decodes n cells encoded with enc from data into dst, growing it only
+// if needed.
+func (c *Compressor) Decode(dst []uint8, enc string, data []byte, n int) ([]uint8, error) {
+	if enc == EncodingRaw && len(data) != n {
+		return dst, errShortFrame
+	}
+	if cap(dst) < n {
+		dst = make([]uint8, n)
+	}
+	dst = dst[:n]
+	switch enc {
+	case EncodingRaw:
+		copy(dst, data)
+		return dst, nil
+	case EncodingRLE:
+		return dst, decodeRLE(dst, data)
+	case EncodingFlate:
+		c.in.Reset(data)
+		if c.flateReader == nil {
+			c.flateReader = flate.NewReader(&c.in)
+		} else if err := c.flateReader.(flate.Resetter).Reset(&c.in, nil); err != nil {
+			return dst, err
+		}
+		if _, err := io.ReadFull(c.flateReader, dst); err != nil {
+			return dst, err
+		}
+		return dst, nil
+	}
+	return dst, fmt.Errorf("stubs: unknown encoding %q", enc)
+}
+
+func appendRLE(dst []byte, cells []uint8) []byte {
+	alive := false
+	run := uint64(0)
+	for _, cell := range cells {
+		if (cell != 0) != alive {
+			dst = binary.AppendUvarint(dst, run)
+			alive = !alive
+			run = 0
+		}
+		run++
+	}
+	return binary.AppendUvarint(dst, run)
+}
+
+var errBadRLE = errors.New("stubs: run lengths do not match the cell count")
+
+func decodeRLE(dst []uint8, data []byte) error {
+	cell := uint8(0)
+	i := 0
+	for len(data) > 0 {
+		run, n := binary.Uvarint(data)
+		if n <= 0 || run > uint64(len(dst)-i) {
+			return errBadRLE
+		}
+		data = data[n:]
+		for end := i + int(run); i < end; i++ {
+			dst[i] = cell
+		}
+		cell ^= 255
+	}
+	if i != len(dst) {
+		return errBadRLE
+	}
+	return nil
+}
+
+// WireStats counts the cell bytes a connection carried before and after
+// encoding. It is safe for concurrent use.
+type WireStats struct {
+	raw  int64
+	wire int64
+}
+
+func (s *WireStats) add(raw, wire int) {
+	atomic.AddInt64(&s.raw, int64(raw))
+	atomic.AddInt64(&s.wire, int64(wire))
+}
+
+// Take returns the bytes counted since the last call and resets the counts.
+func (s *WireStats) Take() (raw, wire int64) {
+	return atomic.SwapInt64(&s.raw, 0), atomic.SwapInt64(&s.wire, 0)
+}
+
+// EncodeWorld flattens world and encodes it with enc.
+func EncodeWorld(enc string, world [][]uint8) ([]byte, error) {
+	var flat []uint8
+	for _, row := range world {
+		flat = append(flat, row...)
+	}
+	var c Compressor
+	return c.Encode(nil, enc, flat)
+}
+
+// DecodeWorld decodes a width by height world encoded by EncodeWorld.
+func DecodeWorld(enc string, cells []byte, width, height int) ([][]uint8, error) {
+	var c Compressor
+	flat, err := c.Decode(nil, enc, cells, width*height)
+	if err != nil {
+		return nil, err
+	}
+	world := make([][]uint8, height)
+	for y := range world {
+		world[y] = flat[y*width : (y+1)*width]
+	}
+	return world, nil
+}
@@ -0,0 +1,39 @@
+package stubs
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestEncodings round-trips a board through every encoding, reusing one
+// Compressor the way a connection does.
+func TestEncodings(t *testing.T) {
+	data, err := os.ReadFile("../images/512x512.pgm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells := []byte(strings.Fields(string(data))[4])
+	var c Compressor
+	for _, enc := range append([]string{EncodingRaw}, Encodings...) {
+		for _, board := range [][]uint8{cells, cells[:1], nil, bytes.Repeat([]uint8{255}, 100)} {
+			encoded, err := c.Encode(nil, enc, board)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := c.Decode(nil, enc, encoded, len(board))
+			if err != nil {
+				t.Fatalf("%q: %v", enc, err)
+			}
+			if !bytes.Equal(decoded, board) {
+				t.Fatalf("%q: %v cells did not survive the round trip", enc, len(board))
+			}
+		}
+		encoded, _ := c.Encode(nil, enc, cells)
+		t.Logf("%q: %v cells in %v bytes", enc, len(cells), len(encoded))
+	}
+	if ChooseEncoding([]string{"zstd", EncodingFlate}, Encodings) != EncodingFlate || ChooseEncoding(nil, Encodings) != EncodingRaw {
+		t.Error("ChooseEncoding did not pick the first shared encoding")
+	}
+}
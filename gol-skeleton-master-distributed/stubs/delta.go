@@ -0,0 +1,63 @@
+package stubs
+
+import "errors"
+
+// TileBounds returns the cells [x0, x1) x [y0, y1) covered by a tile of a
+// width by height world split into size by size tiles, numbered row by row.
+func TileBounds(width, height, size, tile int) (x0, y0, x1, y1 int) {
+	tilesX := (width + size - 1) / size
+	x0, y0 = tile%tilesX*size, tile/tilesX*size
+	x1, y1 = x0+size, y0+size
+	if x1 > width {
+		x1 = width
+	}
+	if y1 > height {
+		y1 = height
+	}
+	return x0, y0, x1, y1
+}
+
+// AppendTile appends the cells of a tile of world, row by row, to dst.
+func AppendTile(dst, world []uint8, width, height, size, tile int) []uint8 {
+	x0, y0, x1, y1 := TileBounds(width, height, size, tile)
+	for y := y0; y < y1; y++ {
+		dst = append(dst, world[y*width+x0:y*width+x1]...)
+	}
+	return dst
+}
+
+var errBadDelta = errors.New("stubs: delta does not fit the world")
+
+// ApplyWorldDelta brings world, a flattened copy of the broker's world, up to
+// date with res and returns it. A full response replaces world.
+func ApplyWorldDelta(world []uint8, res *GetWorldDeltaResponse) ([]uint8, error) {
+	width, height := res.ImageWidth, res.ImageHeight
+	var c Compressor
+	if res.Full {
+		return c.Decode(world, res.Encoding, res.Cells, width*height)
+	}
+	if len(world) != width*height || res.TileSize <= 0 {
+		return world, errBadDelta
+	}
+	tilesX := (width + res.TileSize - 1) / res.TileSize
+	tilesY := (height + res.TileSize - 1) / res.TileSize
+	n := 0
+	for _, tile := range res.Tiles {
+		if tile < 0 || tile >= tilesX*tilesY {
+			return world, errBadDelta
+		}
+		x0, y0, x1, y1 := TileBounds(width, height, res.TileSize, tile)
+		n += (x1 - x0) * (y1 - y0)
+	}
+	cells, err := c.Decode(nil, res.Encoding, res.Cells, n)
+	if err != nil {
+		return world, err
+	}
+	for _, tile := range res.Tiles {
+		x0, y0, x1, y1 := TileBounds(width, height, res.TileSize, tile)
+		for y := y0; y < y1; y++ {
+			cells = cells[copy(world[y*width+x0:y*width+x1], cells):]
+		}
+	}
+	return world, nil
+}
@@ -0,0 +1,164 @@
+package stubs
+
+import (
+	"sync"
+	"time"
+
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// Kinds of RunEvent.
+const (
+	// EventTurn reports that CompletedTurns turns are done. Consecutive turn
+	// events are folded into the latest one, so a slow subscriber sees fewer.
+	EventTurn = "turn"
+	// EventAlive reports CellsCount alive cells after CompletedTurns turns. It
+	// is sent every AliveInterval while a run is in progress.
+	EventAlive = "alive"
+	// EventState reports that the run entered State.
+	EventState = "state"
+	// EventCellsFlipped reports the Cells an edit flipped after CompletedTurns
+	// turns.
+	EventCellsFlipped = "cells-flipped"
+	// EventSpeed reports that the run's target speed is now TurnsPerSecond,
+	// or unlimited if it is 0.
+	EventSpeed = "speed"
+	// EventWorkerFailed reports that the broker left out Worker because of Error.
+	EventWorkerFailed = "worker-failed"
+	// EventFinished reports that the run ended after CompletedTurns turns with
+	// CellsCount cells alive, because of Error if it is set or because its stop
+	// condition Reason held, after finding a cycle of Period turns for StopCycle.
+	EventFinished = "finished"
+)
+
+// States of an EventState.
+const (
+	StateExecuting = "executing"
+	StatePaused    = "paused"
+)
+
+// AliveInterval is how often EventAlive is sent during a run.
+const AliveInterval = 2 * time.Second
+
+// maxEvents is how many events an EventLog keeps for subscribers that fall behind.
+const maxEvents = 1024
+
+// RunEvent is something that happened during run Run. Seq numbers events in
+// the order they happened.
+type RunEvent struct {
+	Seq            uint64
+	Kind           string
+	Run            int
+	CompletedTurns int
+	CellsCount     int
+	State          string
+	Worker         string
+	Error          string
+	Cells          []util.Cell
+	TurnsPerSecond float64
+	Reason         string
+	Period         int
+}
+
+// SubscribeRequest asks for the events of run Run (0 for every run) after
+// the one numbered After, waiting up to Wait for one to happen.
+type SubscribeRequest struct {
+	Run   int
+	After uint64
+	Wait  time.Duration
+	Token string
+}
+
+// SubscribeResponse lists the events in order. It is empty if none happened
+// within the wait. Missed is set if events after After were dropped before the
+// subscriber asked for them.
+type SubscribeResponse struct {
+	Events []RunEvent
+	Missed bool
+}
+
+// EventLog keeps recent events for subscribers to wait on. The zero value is
+// ready to use and it is safe for concurrent use.
+type EventLog struct {
+	mu      sync.Mutex
+	events  []RunEvent
+	seq     uint64
+	dropped uint64
+	signal  chan struct{}
+}
+
+// Add numbers e and appends it to the log, waking any waiting subscribers.
+func (l *EventLog) Add(e RunEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n := len(l.events); n > 0 && e.Kind == EventTurn && l.events[n-1].Kind == EventTurn && l.events[n-1].Run == e.Run {
+		l.events = l.events[:n-1]
+	}
+	if len(l.events) == maxEvents {
+		l.dropped = l.events[maxEvents/2-1].Seq
+		l.events = append(l.events[:0], l.events[maxEvents/2:]...)
+	}
+	l.seq++
+	e.Seq = l.seq
+	l.events = append(l.events, e)
+	if l.signal != nil {
+		close(l.signal)
+		l.signal = nil
+	}
+}
+
+// maxWait caps SubscribeRequest.Wait.
+const maxWait = time.Minute
+
+// Wait answers req from the log, waiting up to req.Wait, or a minute at most,
+// for a matching event.
+func (l *EventLog) Wait(req *SubscribeRequest, res *SubscribeResponse) {
+	wait := req.Wait
+	if wait > maxWait {
+		wait = maxWait
+	}
+	var timeout <-chan time.Time
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	after := req.After
+	l.mu.Lock()
+	if after > l.seq {
+		// The subscriber's events came from a log that has since been
+		// replaced, e.g. by restarting the broker.
+		after = 0
+		res.Missed = true
+	}
+	res.Missed = res.Missed || after < l.dropped
+	l.mu.Unlock()
+	for {
+		l.mu.Lock()
+		res.Events = res.Events[:0]
+		for _, e := range l.events {
+			if e.Seq > after && (req.Run == 0 || e.Run == req.Run) {
+				res.Events = append(res.Events, e)
+			}
+		}
+		if len(res.Events) > 0 || wait <= 0 {
+			l.mu.Unlock()
+			return
+		}
+		// Skip events of other runs next time round.
+		if n := len(l.events); n > 0 {
+			after = l.events[n-1].Seq
+		}
+		if l.signal == nil {
+			l.signal = make(chan struct{})
+		}
+		signal := l.signal
+		l.mu.Unlock()
+
+		select {
+		case <-signal:
+		case <-timeout:
+			return
+		}
+	}
+}
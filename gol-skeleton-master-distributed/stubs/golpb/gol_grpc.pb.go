@@ -0,0 +1,803 @@
+// Protocol for driving the Game of Life broker and its workers over gRPC.
+//
+// The Go code in stubs/golpb is generated from this file; run go generate in
+// stubs after changing it. stubs/proto.go copies the generated messages to and
+// from the structs in stubs.go by field name, so a field here must keep the
+// name of its Go counterpart, e.g. image_width for ImageWidth. Other languages
+// generate clients from this file as usual, e.g.
+//
+//   python -m grpc_tools.protoc -I stubs --python_out=. --grpc_python_out=. stubs/gol.proto
+//
+// Worlds are sent row by row; a cell is 255 when alive and 0 when dead.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: gol.proto
+
+package golpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Broker_Process_FullMethodName        = "/gol.Broker/Process"
+	Broker_GetWorld_FullMethodName       = "/gol.Broker/GetWorld"
+	Broker_GetWorldDelta_FullMethodName  = "/gol.Broker/GetWorldDelta"
+	Broker_GetAliveCells_FullMethodName  = "/gol.Broker/GetAliveCells"
+	Broker_Pause_FullMethodName          = "/gol.Broker/Pause"
+	Broker_Resume_FullMethodName         = "/gol.Broker/Resume"
+	Broker_StopProcessing_FullMethodName = "/gol.Broker/StopProcessing"
+	Broker_Shutdown_FullMethodName       = "/gol.Broker/Shutdown"
+	Broker_Hello_FullMethodName          = "/gol.Broker/Hello"
+	Broker_Subscribe_FullMethodName      = "/gol.Broker/Subscribe"
+	Broker_GetRegion_FullMethodName      = "/gol.Broker/GetRegion"
+	Broker_SetCells_FullMethodName       = "/gol.Broker/SetCells"
+	Broker_ClearRegion_FullMethodName    = "/gol.Broker/ClearRegion"
+	Broker_PastePattern_FullMethodName   = "/gol.Broker/PastePattern"
+	Broker_Step_FullMethodName           = "/gol.Broker/Step"
+	Broker_SetSpeed_FullMethodName       = "/gol.Broker/SetSpeed"
+)
+
+// BrokerClient is the client API for Broker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BrokerClient interface {
+	Process(ctx context.Context, in *EngineRequest, opts ...grpc.CallOption) (*EngineResponse, error)
+	GetWorld(ctx context.Context, in *GetWorldRequest, opts ...grpc.CallOption) (*GetWorldResponse, error)
+	GetWorldDelta(ctx context.Context, in *GetWorldDeltaRequest, opts ...grpc.CallOption) (*GetWorldDeltaResponse, error)
+	GetAliveCells(ctx context.Context, in *AliveCellsCountRequest, opts ...grpc.CallOption) (*AliveCellsCountResponse, error)
+	Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*PauseResponse, error)
+	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error)
+	StopProcessing(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+	Hello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloResponse, error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error)
+	GetRegion(ctx context.Context, in *GetRegionRequest, opts ...grpc.CallOption) (*GetRegionResponse, error)
+	SetCells(ctx context.Context, in *SetCellsRequest, opts ...grpc.CallOption) (*EditResponse, error)
+	ClearRegion(ctx context.Context, in *ClearRegionRequest, opts ...grpc.CallOption) (*EditResponse, error)
+	PastePattern(ctx context.Context, in *PastePatternRequest, opts ...grpc.CallOption) (*EditResponse, error)
+	Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*StepResponse, error)
+	SetSpeed(ctx context.Context, in *SetSpeedRequest, opts ...grpc.CallOption) (*SetSpeedResponse, error)
+}
+
+type brokerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBrokerClient(cc grpc.ClientConnInterface) BrokerClient {
+	return &brokerClient{cc}
+}
+
+func (c *brokerClient) Process(ctx context.Context, in *EngineRequest, opts ...grpc.CallOption) (*EngineResponse, error) {
+	out := new(EngineResponse)
+	err := c.cc.Invoke(ctx, Broker_Process_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) GetWorld(ctx context.Context, in *GetWorldRequest, opts ...grpc.CallOption) (*GetWorldResponse, error) {
+	out := new(GetWorldResponse)
+	err := c.cc.Invoke(ctx, Broker_GetWorld_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) GetWorldDelta(ctx context.Context, in *GetWorldDeltaRequest, opts ...grpc.CallOption) (*GetWorldDeltaResponse, error) {
+	out := new(GetWorldDeltaResponse)
+	err := c.cc.Invoke(ctx, Broker_GetWorldDelta_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) GetAliveCells(ctx context.Context, in *AliveCellsCountRequest, opts ...grpc.CallOption) (*AliveCellsCountResponse, error) {
+	out := new(AliveCellsCountResponse)
+	err := c.cc.Invoke(ctx, Broker_GetAliveCells_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*PauseResponse, error) {
+	out := new(PauseResponse)
+	err := c.cc.Invoke(ctx, Broker_Pause_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error) {
+	out := new(ResumeResponse)
+	err := c.cc.Invoke(ctx, Broker_Resume_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) StopProcessing(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
+	out := new(StopResponse)
+	err := c.cc.Invoke(ctx, Broker_StopProcessing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
+	out := new(ShutdownResponse)
+	err := c.cc.Invoke(ctx, Broker_Shutdown_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) Hello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloResponse, error) {
+	out := new(HelloResponse)
+	err := c.cc.Invoke(ctx, Broker_Hello_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error) {
+	out := new(SubscribeResponse)
+	err := c.cc.Invoke(ctx, Broker_Subscribe_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) GetRegion(ctx context.Context, in *GetRegionRequest, opts ...grpc.CallOption) (*GetRegionResponse, error) {
+	out := new(GetRegionResponse)
+	err := c.cc.Invoke(ctx, Broker_GetRegion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) SetCells(ctx context.Context, in *SetCellsRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
+	err := c.cc.Invoke(ctx, Broker_SetCells_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) ClearRegion(ctx context.Context, in *ClearRegionRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
+	err := c.cc.Invoke(ctx, Broker_ClearRegion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) PastePattern(ctx context.Context, in *PastePatternRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
+	err := c.cc.Invoke(ctx, Broker_PastePattern_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*StepResponse, error) {
+	out := new(StepResponse)
+	err := c.cc.Invoke(ctx, Broker_Step_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerClient) SetSpeed(ctx context.Context, in *SetSpeedRequest, opts ...grpc.CallOption) (*SetSpeedResponse, error) {
+	out := new(SetSpeedResponse)
+	err := c.cc.Invoke(ctx, Broker_SetSpeed_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BrokerServer is the server API for Broker service.
+// All implementations must embed UnimplementedBrokerServer
+// for forward compatibility
+type BrokerServer interface {
+	Process(context.Context, *EngineRequest) (*EngineResponse, error)
+	GetWorld(context.Context, *GetWorldRequest) (*GetWorldResponse, error)
+	GetWorldDelta(context.Context, *GetWorldDeltaRequest) (*GetWorldDeltaResponse, error)
+	GetAliveCells(context.Context, *AliveCellsCountRequest) (*AliveCellsCountResponse, error)
+	Pause(context.Context, *PauseRequest) (*PauseResponse, error)
+	Resume(context.Context, *ResumeRequest) (*ResumeResponse, error)
+	StopProcessing(context.Context, *StopRequest) (*StopResponse, error)
+	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+	Hello(context.Context, *HelloRequest) (*HelloResponse, error)
+	Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error)
+	GetRegion(context.Context, *GetRegionRequest) (*GetRegionResponse, error)
+	SetCells(context.Context, *SetCellsRequest) (*EditResponse, error)
+	ClearRegion(context.Context, *ClearRegionRequest) (*EditResponse, error)
+	PastePattern(context.Context, *PastePatternRequest) (*EditResponse, error)
+	Step(context.Context, *StepRequest) (*StepResponse, error)
+	SetSpeed(context.Context, *SetSpeedRequest) (*SetSpeedResponse, error)
+	mustEmbedUnimplementedBrokerServer()
+}
+
+// UnimplementedBrokerServer must be embedded to have forward compatible implementations.
+type UnimplementedBrokerServer struct {
+}
+
+func (UnimplementedBrokerServer) Process(context.Context, *EngineRequest) (*EngineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Process not implemented")
+}
+func (UnimplementedBrokerServer) GetWorld(context.Context, *GetWorldRequest) (*GetWorldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorld not implemented")
+}
+func (UnimplementedBrokerServer) GetWorldDelta(context.Context, *GetWorldDeltaRequest) (*GetWorldDeltaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorldDelta not implemented")
+}
+func (UnimplementedBrokerServer) GetAliveCells(context.Context, *AliveCellsCountRequest) (*AliveCellsCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAliveCells not implemented")
+}
+func (UnimplementedBrokerServer) Pause(context.Context, *PauseRequest) (*PauseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedBrokerServer) Resume(context.Context, *ResumeRequest) (*ResumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedBrokerServer) StopProcessing(context.Context, *StopRequest) (*StopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopProcessing not implemented")
+}
+func (UnimplementedBrokerServer) Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
+}
+func (UnimplementedBrokerServer) Hello(context.Context, *HelloRequest) (*HelloResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hello not implemented")
+}
+func (UnimplementedBrokerServer) Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedBrokerServer) GetRegion(context.Context, *GetRegionRequest) (*GetRegionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegion not implemented")
+}
+func (UnimplementedBrokerServer) SetCells(context.Context, *SetCellsRequest) (*EditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCells not implemented")
+}
+func (UnimplementedBrokerServer) ClearRegion(context.Context, *ClearRegionRequest) (*EditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearRegion not implemented")
+}
+func (UnimplementedBrokerServer) PastePattern(context.Context, *PastePatternRequest) (*EditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PastePattern not implemented")
+}
+func (UnimplementedBrokerServer) Step(context.Context, *StepRequest) (*StepResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Step not implemented")
+}
+func (UnimplementedBrokerServer) SetSpeed(context.Context, *SetSpeedRequest) (*SetSpeedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSpeed not implemented")
+}
+func (UnimplementedBrokerServer) mustEmbedUnimplementedBrokerServer() {}
+
+// UnsafeBrokerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BrokerServer will
+// result in compilation errors.
+type UnsafeBrokerServer interface {
+	mustEmbedUnimplementedBrokerServer()
+}
+
+func RegisterBrokerServer(s grpc.ServiceRegistrar, srv BrokerServer) {
+	s.RegisterService(&Broker_ServiceDesc, srv)
+}
+
+func _Broker_Process_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EngineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Process(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_Process_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Process(ctx, req.(*EngineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_GetWorld_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).GetWorld(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_GetWorld_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).GetWorld(ctx, req.(*GetWorldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_GetWorldDelta_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorldDeltaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).GetWorldDelta(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_GetWorldDelta_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).GetWorldDelta(ctx, req.(*GetWorldDeltaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_GetAliveCells_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AliveCellsCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).GetAliveCells(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_GetAliveCells_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).GetAliveCells(ctx, req.(*AliveCellsCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_Pause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Pause(ctx, req.(*PauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_Resume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Resume(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_StopProcessing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).StopProcessing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_StopProcessing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).StopProcessing(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_Shutdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Shutdown(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_Hello_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HelloRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Hello(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_Hello_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Hello(ctx, req.(*HelloRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Subscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_Subscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Subscribe(ctx, req.(*SubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_GetRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).GetRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_GetRegion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).GetRegion(ctx, req.(*GetRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_SetCells_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCellsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).SetCells(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_SetCells_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).SetCells(ctx, req.(*SetCellsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_ClearRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).ClearRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_ClearRegion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).ClearRegion(ctx, req.(*ClearRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_PastePattern_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PastePatternRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).PastePattern(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_PastePattern_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).PastePattern(ctx, req.(*PastePatternRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_Step_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Step(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_Step_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Step(ctx, req.(*StepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Broker_SetSpeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSpeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).SetSpeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Broker_SetSpeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).SetSpeed(ctx, req.(*SetSpeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Broker_ServiceDesc is the grpc.ServiceDesc for Broker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Broker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gol.Broker",
+	HandlerType: (*BrokerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Process",
+			Handler:    _Broker_Process_Handler,
+		},
+		{
+			MethodName: "GetWorld",
+			Handler:    _Broker_GetWorld_Handler,
+		},
+		{
+			MethodName: "GetWorldDelta",
+			Handler:    _Broker_GetWorldDelta_Handler,
+		},
+		{
+			MethodName: "GetAliveCells",
+			Handler:    _Broker_GetAliveCells_Handler,
+		},
+		{
+			MethodName: "Pause",
+			Handler:    _Broker_Pause_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _Broker_Resume_Handler,
+		},
+		{
+			MethodName: "StopProcessing",
+			Handler:    _Broker_StopProcessing_Handler,
+		},
+		{
+			MethodName: "Shutdown",
+			Handler:    _Broker_Shutdown_Handler,
+		},
+		{
+			MethodName: "Hello",
+			Handler:    _Broker_Hello_Handler,
+		},
+		{
+			MethodName: "Subscribe",
+			Handler:    _Broker_Subscribe_Handler,
+		},
+		{
+			MethodName: "GetRegion",
+			Handler:    _Broker_GetRegion_Handler,
+		},
+		{
+			MethodName: "SetCells",
+			Handler:    _Broker_SetCells_Handler,
+		},
+		{
+			MethodName: "ClearRegion",
+			Handler:    _Broker_ClearRegion_Handler,
+		},
+		{
+			MethodName: "PastePattern",
+			Handler:    _Broker_PastePattern_Handler,
+		},
+		{
+			MethodName: "Step",
+			Handler:    _Broker_Step_Handler,
+		},
+		{
+			MethodName: "SetSpeed",
+			Handler:    _Broker_SetSpeed_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gol.proto",
+}
+
+const (
+	GolWorker_CalculateNextState_FullMethodName = "/gol.GolWorker/CalculateNextState"
+	GolWorker_Hello_FullMethodName              = "/gol.GolWorker/Hello"
+)
+
+// GolWorkerClient is the client API for GolWorker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GolWorkerClient interface {
+	CalculateNextState(ctx context.Context, in *WorkerRequest, opts ...grpc.CallOption) (*WorkerResponse, error)
+	Hello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloResponse, error)
+}
+
+type golWorkerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGolWorkerClient(cc grpc.ClientConnInterface) GolWorkerClient {
+	return &golWorkerClient{cc}
+}
+
+func (c *golWorkerClient) CalculateNextState(ctx context.Context, in *WorkerRequest, opts ...grpc.CallOption) (*WorkerResponse, error) {
+	out := new(WorkerResponse)
+	err := c.cc.Invoke(ctx, GolWorker_CalculateNextState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *golWorkerClient) Hello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloResponse, error) {
+	out := new(HelloResponse)
+	err := c.cc.Invoke(ctx, GolWorker_Hello_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GolWorkerServer is the server API for GolWorker service.
+// All implementations must embed UnimplementedGolWorkerServer
+// for forward compatibility
+type GolWorkerServer interface {
+	CalculateNextState(context.Context, *WorkerRequest) (*WorkerResponse, error)
+	Hello(context.Context, *HelloRequest) (*HelloResponse, error)
+	mustEmbedUnimplementedGolWorkerServer()
+}
+
+// UnimplementedGolWorkerServer must be embedded to have forward compatible implementations.
+type UnimplementedGolWorkerServer struct {
+}
+
+func (UnimplementedGolWorkerServer) CalculateNextState(context.Context, *WorkerRequest) (*WorkerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateNextState not implemented")
+}
+func (UnimplementedGolWorkerServer) Hello(context.Context, *HelloRequest) (*HelloResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hello not implemented")
+}
+func (UnimplementedGolWorkerServer) mustEmbedUnimplementedGolWorkerServer() {}
+
+// UnsafeGolWorkerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GolWorkerServer will
+// result in compilation errors.
+type UnsafeGolWorkerServer interface {
+	mustEmbedUnimplementedGolWorkerServer()
+}
+
+func RegisterGolWorkerServer(s grpc.ServiceRegistrar, srv GolWorkerServer) {
+	s.RegisterService(&GolWorker_ServiceDesc, srv)
+}
+
+func _GolWorker_CalculateNextState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GolWorkerServer).CalculateNextState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GolWorker_CalculateNextState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GolWorkerServer).CalculateNextState(ctx, req.(*WorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GolWorker_Hello_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HelloRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GolWorkerServer).Hello(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GolWorker_Hello_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GolWorkerServer).Hello(ctx, req.(*HelloRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GolWorker_ServiceDesc is the grpc.ServiceDesc for GolWorker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GolWorker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gol.GolWorker",
+	HandlerType: (*GolWorkerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CalculateNextState",
+			Handler:    _GolWorker_CalculateNextState_Handler,
+		},
+		{
+			MethodName: "Hello",
+			Handler:    _GolWorker_Hello_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gol.proto",
+}
@@ -0,0 +1,355 @@
+package stubs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/rpc"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"uk.ac.bris.cs/gameoflife/stubs/golpb"
+)
+
+// The broker and workers keep their net/rpc style methods, func(req *T, res *U) error,
+// as the one service layer. The helpers below serve those methods as the
+// services generated from gol.proto, and call them by their net/rpc names.
+
+// BrokerService is the broker's net/rpc service, served over gRPC by RegisterBroker.
+type BrokerService interface {
+	Process(req *EngineRequest, res *EngineResponse) error
+	GetWorld(req *GetWorldRequest, res *GetWorldResponse) error
+	GetWorldDelta(req *GetWorldDeltaRequest, res *GetWorldDeltaResponse) error
+	GetAliveCells(req *AliveCellsCountRequest, res *AliveCellsCountResponse) error
+	Pause(req *PauseRequest, res *PauseResponse) error
+	Resume(req *ResumeRequest, res *ResumeResponse) error
+	StopProcessing(req *StopRequest, res *StopResponse) error
+	Shutdown(req *ShutdownRequest, res *ShutdownResponse) error
+	Hello(req *HelloRequest, res *HelloResponse) error
+	Subscribe(req *SubscribeRequest, res *SubscribeResponse) error
+	GetRegion(req *GetRegionRequest, res *GetRegionResponse) error
+	SetCells(req *SetCellsRequest, res *EditResponse) error
+	ClearRegion(req *ClearRegionRequest, res *EditResponse) error
+	PastePattern(req *PastePatternRequest, res *EditResponse) error
+	Step(req *StepRequest, res *StepResponse) error
+	SetSpeed(req *SetSpeedRequest, res *SetSpeedResponse) error
+}
+
+// WorkerService is a worker's net/rpc service, served over gRPC by RegisterWorker.
+type WorkerService interface {
+	CalculateNextState(req *WorkerRequest, res *WorkerResponse) error
+	Hello(req *HelloRequest, res *HelloResponse) error
+}
+
+// RegisterBroker serves b as the gRPC service gol.Broker.
+func RegisterBroker(s *grpc.Server, b BrokerService) {
+	golpb.RegisterBrokerServer(s, brokerServer{broker: b})
+}
+
+// RegisterWorker serves w as the gRPC service gol.GolWorker.
+func RegisterWorker(s *grpc.Server, w WorkerService) {
+	golpb.RegisterGolWorkerServer(s, workerServer{worker: w})
+}
+
+// serve copies the message in to req, makes the call and copies res to the
+// message out. Errors from the call itself are sent as codes.Unknown, which
+// GRPCClient turns back into an rpc.ServerError.
+func serve(in proto.Message, req interface{}, call func() error, res interface{}, out proto.Message) error {
+	if err := fromProto(in, req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := call(); err != nil {
+		return status.Error(codes.Unknown, err.Error())
+	}
+	if err := toProto(res, out); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+type brokerServer struct {
+	golpb.UnimplementedBrokerServer
+	broker BrokerService
+}
+
+func (s brokerServer) Process(_ context.Context, in *golpb.EngineRequest) (*golpb.EngineResponse, error) {
+	var req EngineRequest
+	var res EngineResponse
+	out := new(golpb.EngineResponse)
+	return out, serve(in, &req, func() error { return s.broker.Process(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) GetWorld(_ context.Context, in *golpb.GetWorldRequest) (*golpb.GetWorldResponse, error) {
+	var req GetWorldRequest
+	var res GetWorldResponse
+	out := new(golpb.GetWorldResponse)
+	return out, serve(in, &req, func() error { return s.broker.GetWorld(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) GetWorldDelta(_ context.Context, in *golpb.GetWorldDeltaRequest) (*golpb.GetWorldDeltaResponse, error) {
+	var req GetWorldDeltaRequest
+	var res GetWorldDeltaResponse
+	out := new(golpb.GetWorldDeltaResponse)
+	return out, serve(in, &req, func() error { return s.broker.GetWorldDelta(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) GetAliveCells(_ context.Context, in *golpb.AliveCellsCountRequest) (*golpb.AliveCellsCountResponse, error) {
+	var req AliveCellsCountRequest
+	var res AliveCellsCountResponse
+	out := new(golpb.AliveCellsCountResponse)
+	return out, serve(in, &req, func() error { return s.broker.GetAliveCells(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) Pause(_ context.Context, in *golpb.PauseRequest) (*golpb.PauseResponse, error) {
+	var req PauseRequest
+	var res PauseResponse
+	out := new(golpb.PauseResponse)
+	return out, serve(in, &req, func() error { return s.broker.Pause(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) Resume(_ context.Context, in *golpb.ResumeRequest) (*golpb.ResumeResponse, error) {
+	var req ResumeRequest
+	var res ResumeResponse
+	out := new(golpb.ResumeResponse)
+	return out, serve(in, &req, func() error { return s.broker.Resume(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) StopProcessing(_ context.Context, in *golpb.StopRequest) (*golpb.StopResponse, error) {
+	var req StopRequest
+	var res StopResponse
+	out := new(golpb.StopResponse)
+	return out, serve(in, &req, func() error { return s.broker.StopProcessing(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) Shutdown(_ context.Context, in *golpb.ShutdownRequest) (*golpb.ShutdownResponse, error) {
+	var req ShutdownRequest
+	var res ShutdownResponse
+	out := new(golpb.ShutdownResponse)
+	return out, serve(in, &req, func() error { return s.broker.Shutdown(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) Hello(_ context.Context, in *golpb.HelloRequest) (*golpb.HelloResponse, error) {
+	var req HelloRequest
+	var res HelloResponse
+	out := new(golpb.HelloResponse)
+	return out, serve(in, &req, func() error { return s.broker.Hello(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) Subscribe(_ context.Context, in *golpb.SubscribeRequest) (*golpb.SubscribeResponse, error) {
+	var req SubscribeRequest
+	var res SubscribeResponse
+	out := new(golpb.SubscribeResponse)
+	return out, serve(in, &req, func() error { return s.broker.Subscribe(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) GetRegion(_ context.Context, in *golpb.GetRegionRequest) (*golpb.GetRegionResponse, error) {
+	var req GetRegionRequest
+	var res GetRegionResponse
+	out := new(golpb.GetRegionResponse)
+	return out, serve(in, &req, func() error { return s.broker.GetRegion(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) SetCells(_ context.Context, in *golpb.SetCellsRequest) (*golpb.EditResponse, error) {
+	var req SetCellsRequest
+	var res EditResponse
+	out := new(golpb.EditResponse)
+	return out, serve(in, &req, func() error { return s.broker.SetCells(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) ClearRegion(_ context.Context, in *golpb.ClearRegionRequest) (*golpb.EditResponse, error) {
+	var req ClearRegionRequest
+	var res EditResponse
+	out := new(golpb.EditResponse)
+	return out, serve(in, &req, func() error { return s.broker.ClearRegion(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) PastePattern(_ context.Context, in *golpb.PastePatternRequest) (*golpb.EditResponse, error) {
+	var req PastePatternRequest
+	var res EditResponse
+	out := new(golpb.EditResponse)
+	return out, serve(in, &req, func() error { return s.broker.PastePattern(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) Step(_ context.Context, in *golpb.StepRequest) (*golpb.StepResponse, error) {
+	var req StepRequest
+	var res StepResponse
+	out := new(golpb.StepResponse)
+	return out, serve(in, &req, func() error { return s.broker.Step(&req, &res) }, &res, out)
+}
+
+func (s brokerServer) SetSpeed(_ context.Context, in *golpb.SetSpeedRequest) (*golpb.SetSpeedResponse, error) {
+	var req SetSpeedRequest
+	var res SetSpeedResponse
+	out := new(golpb.SetSpeedResponse)
+	return out, serve(in, &req, func() error { return s.broker.SetSpeed(&req, &res) }, &res, out)
+}
+
+type workerServer struct {
+	golpb.UnimplementedGolWorkerServer
+	worker WorkerService
+}
+
+func (s workerServer) CalculateNextState(_ context.Context, in *golpb.WorkerRequest) (*golpb.WorkerResponse, error) {
+	var req WorkerRequest
+	var res WorkerResponse
+	out := new(golpb.WorkerResponse)
+	return out, serve(in, &req, func() error { return s.worker.CalculateNextState(&req, &res) }, &res, out)
+}
+
+func (s workerServer) Hello(_ context.Context, in *golpb.HelloRequest) (*golpb.HelloResponse, error) {
+	var req HelloRequest
+	var res HelloResponse
+	out := new(golpb.HelloResponse)
+	return out, serve(in, &req, func() error { return s.worker.Hello(&req, &res) }, &res, out)
+}
+
+// NewGRPCServer returns a gRPC server that accepts messages as large as a whole world.
+func NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.MaxRecvMsgSize(math.MaxInt32), grpc.MaxSendMsgSize(math.MaxInt32))
+	return grpc.NewServer(opts...)
+}
+
+// GRPCClient calls net/rpc style methods over gRPC, mirroring rpc.Client.
+type GRPCClient struct {
+	conn  *grpc.ClientConn
+	opts  []grpc.CallOption
+	stats *WireStats
+}
+
+// DialGRPC connects to a gRPC server at addr.
+func DialGRPC(addr string, opts ...grpc.DialOption) (*GRPCClient, error) {
+	counted := new(WireStats)
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(math.MaxInt32), grpc.MaxCallSendMsgSize(math.MaxInt32)),
+		grpc.WithStatsHandler(cellStats{counted}),
+	}, opts...)
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GRPCClient{conn: conn, stats: counted}, nil
+}
+
+// Stats returns the bytes of the CalculateNextState messages sent and received
+// so far, before and after compression. gRPC compresses whole messages, so
+// unlike WorkerClientCodec.Stats the counts take in the few bytes of a
+// WorkerRequest or WorkerResponse that are not cells.
+func (c *GRPCClient) Stats() *WireStats {
+	return c.stats
+}
+
+// cellStats is a gRPC stats.Handler counting the payloads of CalculateNextState calls.
+type cellStats struct {
+	stats *WireStats
+}
+
+type cellCallKey struct{}
+
+func (h cellStats) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if info.FullMethodName == golpb.GolWorker_CalculateNextState_FullMethodName {
+		return context.WithValue(ctx, cellCallKey{}, true)
+	}
+	return ctx
+}
+
+func (h cellStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if ctx.Value(cellCallKey{}) == nil {
+		return
+	}
+	switch p := s.(type) {
+	case *stats.OutPayload:
+		h.stats.add(p.Length, p.CompressedLength)
+	case *stats.InPayload:
+		h.stats.add(p.Length, p.CompressedLength)
+	}
+}
+
+func (h cellStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h cellStats) HandleConn(context.Context, stats.ConnStats) {}
+
+// SetEncoding compresses later calls once the server has agreed to enc in
+// Hello. gRPC compresses whole messages, so any encoding other than
+// EncodingRaw turns on its gzip compressor, which the server answers in kind.
+func (c *GRPCClient) SetEncoding(enc string) {
+	c.opts = nil
+	if enc != EncodingRaw {
+		c.opts = []grpc.CallOption{grpc.UseCompressor(gzip.Name)}
+	}
+}
+
+// Call invokes the named method, e.g. stubs.Process, and waits for it to complete.
+// Errors returned by the method itself come back as rpc.ServerError, as with net/rpc.
+func (c *GRPCClient) Call(serviceMethod string, args, reply interface{}) error {
+	in, out, err := protoMessages(serviceMethod)
+	if err != nil {
+		return err
+	}
+	if err := toProto(args, in); err != nil {
+		return err
+	}
+	err = c.conn.Invoke(context.Background(), "/gol."+strings.Replace(serviceMethod, ".", "/", 1), in, out, c.opts...)
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Unknown {
+		return rpc.ServerError(s.Message())
+	}
+	if err != nil {
+		return err
+	}
+	return fromProto(out, reply)
+}
+
+// protoMessages returns empty generated messages for the request and response
+// of a method named as in net/rpc, e.g. "Broker.Process".
+func protoMessages(serviceMethod string) (in, out proto.Message, err error) {
+	dot := strings.Index(serviceMethod, ".")
+	if dot < 0 {
+		return nil, nil, fmt.Errorf("stubs: malformed method name %q", serviceMethod)
+	}
+	service := golpb.File_gol_proto.Services().ByName(protoreflect.Name(serviceMethod[:dot]))
+	if service == nil {
+		return nil, nil, fmt.Errorf("stubs: gol.proto has no service %s", serviceMethod[:dot])
+	}
+	method := service.Methods().ByName(protoreflect.Name(serviceMethod[dot+1:]))
+	if method == nil {
+		return nil, nil, fmt.Errorf("stubs: gol.proto has no method %s", serviceMethod)
+	}
+	inType, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	outType, err := protoregistry.GlobalTypes.FindMessageByName(method.Output().FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	return inType.New().Interface(), outType.New().Interface(), nil
+}
+
+// Go invokes the named method asynchronously and signals completion on done,
+// in the same way as rpc.Client.Go.
+func (c *GRPCClient) Go(serviceMethod string, args, reply interface{}, done chan *rpc.Call) *rpc.Call {
+	if done == nil {
+		done = make(chan *rpc.Call, 1)
+	}
+	call := &rpc.Call{ServiceMethod: serviceMethod, Args: args, Reply: reply, Done: done}
+	go func() {
+		call.Error = c.Call(serviceMethod, args, reply)
+		call.Done <- call
+	}()
+	return call
+}
+
+// Close tears down the connection.
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
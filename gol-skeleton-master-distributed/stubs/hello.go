@@ -0,0 +1,85 @@
+package stubs
+
+import (
+	"fmt"
+	"math"
+)
+
+// Every connection, from a controller to the broker or from the broker to a
+// worker, starts with a Hello call so that peers built from different versions
+// of this package fail at connect time with a clear error rather than
+// misreading each other's messages later.
+
+// ProtocolVersion changes whenever a request, response or frame changes in a
+// way an older peer would misread. Peers only talk if their versions match.
+const ProtocolVersion = 1
+
+// RuleLife is Conway's Game of Life, the only rule implemented so far.
+const RuleLife = "B3/S23"
+
+// Rules lists the rules this build implements.
+var Rules = []string{RuleLife}
+
+// Features a peer may advertise in Hello.
+const (
+	// FeatureNoisy means the peer honours Seed and Probability.
+	FeatureNoisy = "noisy"
+	// FeatureDirtyTiles means a worker copies clean tiles through.
+	FeatureDirtyTiles = "dirty-tiles"
+	// FeatureWorldDelta means a broker answers GetWorldDelta.
+	FeatureWorldDelta = "world-delta"
+	// FeatureEvents means a broker answers Subscribe.
+	FeatureEvents = "events"
+)
+
+// MaxCells is the largest world or block, in cells, that fits in one message.
+const MaxCells = math.MaxInt32
+
+// HasFeature reports whether features contains feature.
+func HasFeature(features []string, feature string) bool {
+	for _, f := range features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckHello returns an error if a peer calling with req cannot be served by
+// this build. peer names the caller in the error, e.g. "controller".
+func CheckHello(peer string, req *HelloRequest) error {
+	if req.Version != ProtocolVersion {
+		return fmt.Errorf("%s speaks protocol version %d, but this build speaks %d", peer, req.Version, ProtocolVersion)
+	}
+	for _, rule := range req.Rules {
+		if !HasFeature(Rules, rule) {
+			return fmt.Errorf("%s asked for rule %s, but this build only implements %v", peer, rule, Rules)
+		}
+	}
+	return nil
+}
+
+// CheckHelloResponse returns an error if the peer at addr that answered res
+// cannot serve this build.
+func CheckHelloResponse(addr string, res *HelloResponse) error {
+	if res.Version != ProtocolVersion {
+		return fmt.Errorf("%s speaks protocol version %d, but this build speaks %d", addr, res.Version, ProtocolVersion)
+	}
+	if !HasFeature(res.Rules, RuleLife) {
+		return fmt.Errorf("%s does not implement rule %s", addr, RuleLife)
+	}
+	return nil
+}
+
+// SayHello opens a connection through call, which is a Call method such as
+// that of *rpc.Client, and checks that the peer at addr can serve this build.
+func SayHello(call func(serviceMethod string, args, reply interface{}) error, serviceMethod, addr string, req *HelloRequest) (*HelloResponse, error) {
+	res := new(HelloResponse)
+	if err := call(serviceMethod, req, res); err != nil {
+		return nil, fmt.Errorf("hello to %s failed, is it running an older version? %v", addr, err)
+	}
+	if err := CheckHelloResponse(addr, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,166 @@
+package stubs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+//go:generate protoc --go_out=golpb --go_opt=paths=source_relative --go-grpc_out=golpb --go-grpc_opt=paths=source_relative gol.proto
+
+// Over gRPC the structs in this package travel as the messages generated from
+// gol.proto into package golpb. toProto and fromProto copy between the two,
+// matching each proto field to the struct field with its name in CamelCase.
+// int and time.Duration fields are int64 in gol.proto, and a []util.Cell is a
+// repeated int64 holding x, y pairs.
+
+var cellType = reflect.TypeOf(util.Cell{})
+
+// toProto copies the struct src points to into the message dst.
+func toProto(src interface{}, dst proto.Message) error {
+	return copyToProto(reflect.ValueOf(src).Elem(), dst.ProtoReflect())
+}
+
+// fromProto copies the message src into the struct dst points to.
+func fromProto(src proto.Message, dst interface{}) error {
+	return copyFromProto(src.ProtoReflect(), reflect.ValueOf(dst).Elem())
+}
+
+// structField finds the field of v that carries fd.
+func structField(v reflect.Value, fd protoreflect.FieldDescriptor) (reflect.Value, error) {
+	parts := strings.Split(string(fd.Name()), "_")
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	f := v.FieldByName(strings.Join(parts, ""))
+	if !f.IsValid() {
+		return f, fmt.Errorf("stubs: %s has no field for %s", v.Type(), fd.FullName())
+	}
+	return f, nil
+}
+
+func copyToProto(v reflect.Value, m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		f, err := structField(v, fd)
+		if err != nil {
+			return err
+		}
+		if f.IsZero() {
+			continue
+		}
+		switch {
+		case fd.IsList() && f.Type().Elem() == cellType:
+			list := m.Mutable(fd).List()
+			for _, cell := range f.Interface().([]util.Cell) {
+				list.Append(protoreflect.ValueOfInt64(int64(cell.X)))
+				list.Append(protoreflect.ValueOfInt64(int64(cell.Y)))
+			}
+		case fd.IsList():
+			list := m.Mutable(fd).List()
+			for j := 0; j < f.Len(); j++ {
+				if fd.Kind() != protoreflect.MessageKind {
+					list.Append(scalarToProto(fd, f.Index(j)))
+					continue
+				}
+				element := list.NewElement()
+				if err := copyToProto(f.Index(j), element.Message()); err != nil {
+					return err
+				}
+				list.Append(element)
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			if err := copyToProto(f, m.Mutable(fd).Message()); err != nil {
+				return err
+			}
+		default:
+			m.Set(fd, scalarToProto(fd, f))
+		}
+	}
+	return nil
+}
+
+func scalarToProto(fd protoreflect.FieldDescriptor, f reflect.Value) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.Int64Kind:
+		return protoreflect.ValueOfInt64(f.Int())
+	case protoreflect.Uint64Kind:
+		return protoreflect.ValueOfUint64(f.Uint())
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(f.Float())
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(f.Bool())
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(f.String())
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(f.Bytes())
+	}
+	panic(fmt.Sprintf("stubs: no Go type for %s", fd.FullName()))
+}
+
+func copyFromProto(m protoreflect.Message, v reflect.Value) error {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		f, err := structField(v, fd)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.Zero(f.Type()))
+		if !m.Has(fd) {
+			continue
+		}
+		switch {
+		case fd.IsList() && f.Type().Elem() == cellType:
+			list := m.Get(fd).List()
+			if list.Len()%2 != 0 {
+				return fmt.Errorf("stubs: %s holds an odd number of coordinates", fd.FullName())
+			}
+			cells := make([]util.Cell, list.Len()/2)
+			for j := range cells {
+				cells[j] = util.Cell{X: int(list.Get(2 * j).Int()), Y: int(list.Get(2*j + 1).Int())}
+			}
+			f.Set(reflect.ValueOf(cells))
+		case fd.IsList():
+			list := m.Get(fd).List()
+			s := reflect.MakeSlice(f.Type(), list.Len(), list.Len())
+			for j := 0; j < list.Len(); j++ {
+				if fd.Kind() != protoreflect.MessageKind {
+					scalarFromProto(list.Get(j), s.Index(j))
+				} else if err := copyFromProto(list.Get(j).Message(), s.Index(j)); err != nil {
+					return err
+				}
+			}
+			f.Set(s)
+		case fd.Kind() == protoreflect.MessageKind:
+			if err := copyFromProto(m.Get(fd).Message(), f); err != nil {
+				return err
+			}
+		default:
+			scalarFromProto(m.Get(fd), f)
+		}
+	}
+	return nil
+}
+
+func scalarFromProto(value protoreflect.Value, f reflect.Value) {
+	switch f.Kind() {
+	case reflect.Int, reflect.Int64:
+		f.SetInt(value.Int())
+	case reflect.Uint64:
+		f.SetUint(value.Uint())
+	case reflect.Float64:
+		f.SetFloat(value.Float())
+	case reflect.Bool:
+		f.SetBool(value.Bool())
+	case reflect.String:
+		f.SetString(value.String())
+	default:
+		f.SetBytes(value.Bytes())
+	}
+}
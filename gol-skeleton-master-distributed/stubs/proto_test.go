@@ -0,0 +1,144 @@
+package stubs
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"uk.ac.bris.cs/gameoflife/stubs/golpb"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// messages holds the struct behind every message in gol.proto.
+var messages = map[protoreflect.Name]interface{}{
+	"HelloRequest":            &HelloRequest{},
+	"HelloResponse":           &HelloResponse{},
+	"EngineRequest":           &EngineRequest{},
+	"StopConditions":          &StopConditions{},
+	"EngineResponse":          &EngineResponse{},
+	"AliveCellsCountRequest":  &AliveCellsCountRequest{},
+	"AliveCellsCountResponse": &AliveCellsCountResponse{},
+	"StopRequest":             &StopRequest{},
+	"StopResponse":            &StopResponse{},
+	"GetWorldRequest":         &GetWorldRequest{},
+	"GetWorldResponse":        &GetWorldResponse{},
+	"GetWorldDeltaRequest":    &GetWorldDeltaRequest{},
+	"GetWorldDeltaResponse":   &GetWorldDeltaResponse{},
+	"GetRegionRequest":        &GetRegionRequest{},
+	"GetRegionResponse":       &GetRegionResponse{},
+	"SetCellsRequest":         &SetCellsRequest{},
+	"ClearRegionRequest":      &ClearRegionRequest{},
+	"PastePatternRequest":     &PastePatternRequest{},
+	"EditResponse":            &EditResponse{},
+	"PauseRequest":            &PauseRequest{},
+	"PauseResponse":           &PauseResponse{},
+	"StepRequest":             &StepRequest{},
+	"StepResponse":            &StepResponse{},
+	"SetSpeedRequest":         &SetSpeedRequest{},
+	"SetSpeedResponse":        &SetSpeedResponse{},
+	"ResumeRequest":           &ResumeRequest{},
+	"ResumeResponse":          &ResumeResponse{},
+	"ShutdownRequest":         &ShutdownRequest{},
+	"ShutdownResponse":        &ShutdownResponse{},
+	"SubscribeRequest":        &SubscribeRequest{},
+	"RunEvent":                &RunEvent{},
+	"SubscribeResponse":       &SubscribeResponse{},
+	"WorkerRequest":           &WorkerRequest{},
+	"WorkerResponse":          &WorkerResponse{},
+}
+
+// fill gives every field of v a distinct non-zero value, counting on from n.
+func fill(v reflect.Value, n *int) {
+	*n++
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		v.SetInt(int64(*n) << 40)
+	case reflect.Uint64:
+		v.SetUint(uint64(*n) << 60)
+	case reflect.Float64:
+		v.SetFloat(float64(*n) + 0.5)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.String:
+		v.SetString(reflect.TypeOf(v.Interface()).String() + string(rune('a'+*n%26)))
+	case reflect.Struct:
+		if v.Type() == cellType {
+			v.Set(reflect.ValueOf(util.Cell{X: *n, Y: -*n}))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			fill(v.Field(i), n)
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 3, 3))
+		for i := 0; i < v.Len(); i++ {
+			if v.Type().Elem().Kind() == reflect.Bool {
+				// Leave one flag false, as in a real list of tiles.
+				v.Index(i).SetBool(i != 1)
+				continue
+			}
+			fill(v.Index(i), n)
+		}
+	}
+}
+
+// TestProtoRoundTrip fills every field of every message, sends it through
+// the generated protobuf code and checks it comes back unchanged.
+func TestProtoRoundTrip(t *testing.T) {
+	descriptors := golpb.File_gol_proto.Messages()
+	if descriptors.Len() != len(messages) {
+		t.Errorf("gol.proto has %d messages, the test knows %d", descriptors.Len(), len(messages))
+	}
+	for i := 0; i < descriptors.Len(); i++ {
+		desc := descriptors.Get(i)
+		message, ok := messages[desc.Name()]
+		if !ok {
+			t.Errorf("no struct for %s", desc.Name())
+			continue
+		}
+		n := 0
+		fill(reflect.ValueOf(message).Elem(), &n)
+
+		messageType, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent := messageType.New().Interface()
+		if err := toProto(message, sent); err != nil {
+			t.Errorf("%s: %v", desc.Name(), err)
+			continue
+		}
+		data, err := proto.Marshal(sent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		received := messageType.New().Interface()
+		if err := proto.Unmarshal(data, received); err != nil {
+			t.Fatal(err)
+		}
+		again := reflect.New(reflect.TypeOf(message).Elem()).Interface()
+		if err := fromProto(received, again); err != nil {
+			t.Errorf("%s: %v", desc.Name(), err)
+			continue
+		}
+		if !reflect.DeepEqual(again, message) {
+			t.Errorf("%s changed in a round trip:\nsent %+v\ngot  %+v", desc.Name(), message, again)
+		}
+	}
+}
+
+// TestProtoErrors checks that a struct missing a field and an odd number of
+// coordinates are refused rather than dropped.
+func TestProtoErrors(t *testing.T) {
+	if err := toProto(&StopRequest{}, &golpb.EditResponse{}); err == nil {
+		t.Error("expected a struct without the message's fields to be refused")
+	}
+	if err := fromProto(&golpb.SetCellsRequest{Cells: []int64{1, 2, 3}}, &SetCellsRequest{}); err == nil {
+		t.Error("expected an odd number of coordinates to be refused")
+	}
+	if _, _, err := protoMessages("Broker.Teleport"); err == nil {
+		t.Error("expected a method missing from gol.proto to be refused")
+	}
+}
@@ -0,0 +1,147 @@
+package stubs
+
+import (
+	"fmt"
+	"hash/maphash"
+	"time"
+)
+
+// Reasons a run stopped before its last turn, reported in the Reason of its
+// EventFinished.
+const (
+	StopExtinct = "extinct"
+	StopAbove   = "above"
+	StopBelow   = "below"
+	StopCycle   = "cycle"
+	StopTimeout = "timeout"
+)
+
+// DescribeStop explains why a run stopped, given the Reason and Period of its
+// EventFinished.
+func DescribeStop(reason string, period int) string {
+	switch reason {
+	case StopExtinct:
+		return "no cells are alive"
+	case StopAbove:
+		return "too many cells are alive"
+	case StopBelow:
+		return "too few cells are alive"
+	case StopCycle:
+		return fmt.Sprintf("the world repeats every %d turns", period)
+	case StopTimeout:
+		return "out of time"
+	}
+	return reason
+}
+
+// StopConditions end a run before its last turn as soon as one of them holds
+// after a turn. The zero value sets none.
+type StopConditions struct {
+	// Extinct stops the run when no cell is alive.
+	Extinct bool
+	// Above, if positive, stops the run when more than Above cells are alive.
+	Above int
+	// Below, if positive, stops the run when fewer than Below cells are alive.
+	Below int
+	// Period, if positive, stops the run when the world repeats within Period
+	// turns: 1 catches still lifes, 2 blinkers as well, and so on.
+	Period int
+	// Timeout, if positive, stops the run once it has gone on this long,
+	// pauses included, even in the middle of a turn.
+	Timeout time.Duration
+}
+
+// maxPeriod caps StopConditions.Period, since a world is remembered for every
+// turn of the period.
+const maxPeriod = 1000
+
+// Validate reports conditions that can never be met or are too costly to check.
+func (c StopConditions) Validate() error {
+	if c.Above < 0 || c.Below < 0 || c.Period < 0 || c.Timeout < 0 {
+		return fmt.Errorf("stop conditions must not be negative: %+v", c)
+	}
+	if c.Period > maxPeriod {
+		return fmt.Errorf("cannot look for cycles longer than %d turns, not %d", maxPeriod, c.Period)
+	}
+	return nil
+}
+
+// StopWatch checks StopConditions after every turn of a run. Worlds are told
+// apart by a 64-bit hash, so a cycle is found with the odds of a collision
+// against it.
+type StopWatch struct {
+	conditions StopConditions
+	started    time.Time
+	seed       maphash.Seed
+	hashes     []uint64 // the last Period worlds, oldest first
+}
+
+// NewStopWatch starts checking conditions for a run starting from world, a
+// generation flattened row by row.
+func NewStopWatch(conditions StopConditions, world []uint8) *StopWatch {
+	w := &StopWatch{conditions: conditions, started: time.Now(), seed: maphash.MakeSeed()}
+	w.Forget(world)
+	return w
+}
+
+// Forget drops the remembered worlds but world, after it was edited.
+func (w *StopWatch) Forget(world []uint8) {
+	w.hashes = w.hashes[:0]
+	if w.conditions.Period > 0 {
+		w.hashes = append(w.hashes, w.hash(world))
+	}
+}
+
+func (w *StopWatch) hash(world []uint8) uint64 {
+	var h maphash.Hash
+	h.SetSeed(w.seed)
+	_, _ = h.Write(world)
+	return h.Sum64()
+}
+
+// Check is called with the world after each turn and its number of alive
+// cells, or -1 if no condition needs it. It returns the reason to stop, with
+// the period of the cycle for StopCycle, or "" to carry on.
+func (w *StopWatch) Check(world []uint8, alive int) (reason string, period int) {
+	c := w.conditions
+	switch {
+	case c.Extinct && alive == 0:
+		return StopExtinct, 0
+	case c.Above > 0 && alive > c.Above:
+		return StopAbove, 0
+	case c.Below > 0 && alive >= 0 && alive < c.Below:
+		return StopBelow, 0
+	}
+	if c.Period > 0 {
+		h := w.hash(world)
+		for i := len(w.hashes) - 1; i >= 0; i-- {
+			if w.hashes[i] == h {
+				return StopCycle, len(w.hashes) - i
+			}
+		}
+		if len(w.hashes) == c.Period {
+			w.hashes = append(w.hashes[:0], w.hashes[1:]...)
+		}
+		w.hashes = append(w.hashes, h)
+	}
+	if c.Timeout > 0 && time.Since(w.started) >= c.Timeout {
+		return StopTimeout, 0
+	}
+	return "", 0
+}
+
+// Remaining returns how long the run has left before its Timeout, and false if
+// it has none. Runs start a timer with it, so that they stop on time while
+// paused, held back to a speed or waiting for a slow worker, not only when
+// Check is next called.
+func (w *StopWatch) Remaining() (time.Duration, bool) {
+	if w.conditions.Timeout <= 0 {
+		return 0, false
+	}
+	return time.Until(w.started.Add(w.conditions.Timeout)), true
+}
+
+// NeedsAlive reports whether Check needs the number of alive cells.
+func (w *StopWatch) NeedsAlive() bool {
+	return w.conditions.Extinct || w.conditions.Above > 0 || w.conditions.Below > 0
+}
@@ -0,0 +1,49 @@
+package stubs
+
+import "testing"
+
+// TestStopWatch checks that a cycle is found once the world repeats within the
+// period, and not when the period is too short for it.
+func TestStopWatch(t *testing.T) {
+	// A blinker in a 5x5 world, standing up and lying down.
+	var up, down [25]uint8
+	for i := 1; i <= 3; i++ {
+		up[i*5+2], down[2*5+i] = 255, 255
+	}
+	for _, test := range []struct {
+		period int
+		turn   int
+	}{{1, 0}, {2, 2}, {3, 2}} {
+		w := NewStopWatch(StopConditions{Period: test.period}, up[:])
+		turn := 0
+		for i := 1; i <= 4 && turn == 0; i++ {
+			world := down[:]
+			if i%2 == 0 {
+				world = up[:]
+			}
+			if reason, period := w.Check(world, -1); reason != "" {
+				if reason != StopCycle || period != 2 {
+					t.Errorf("period %d: expected a cycle of 2, got %q and %d", test.period, reason, period)
+				}
+				turn = i
+			}
+		}
+		if turn != test.turn {
+			t.Errorf("period %d: expected to stop at turn %d, got %d", test.period, test.turn, turn)
+		}
+	}
+
+	w := NewStopWatch(StopConditions{Extinct: true, Below: 2}, up[:])
+	if reason, _ := w.Check(up[:], 3); reason != "" {
+		t.Errorf("stopped with 3 cells alive: %q", reason)
+	}
+	if reason, _ := w.Check(up[:], 1); reason != StopBelow {
+		t.Errorf("expected %q with 1 cell alive, got %q", StopBelow, reason)
+	}
+	if reason, _ := w.Check(up[:], 0); reason != StopExtinct {
+		t.Errorf("expected %q with no cells alive, got %q", StopExtinct, reason)
+	}
+	if (StopConditions{Period: maxPeriod + 1}).Validate() == nil || (StopConditions{Below: -1}).Validate() == nil {
+		t.Error("expected conditions that cannot be checked to be refused")
+	}
+}
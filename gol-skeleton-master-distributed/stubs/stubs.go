@@ -1,69 +1,291 @@
 package stubs
 
+import "uk.ac.bris.cs/gameoflife/util"
+
 const (
 	Process            = "Broker.Process"
 	GetAliveCells      = "Broker.GetAliveCells"
 	StopProcessing     = "Broker.StopProcessing"
 	GetWorld           = "Broker.GetWorld"
+	GetWorldDelta      = "Broker.GetWorldDelta"
 	Pause              = "Broker.Pause"
 	Resume             = "Broker.Resume"
 	Shutdown           = "Broker.Shutdown"
+	Hello              = "Broker.Hello"
+	Subscribe          = "Broker.Subscribe"
+	GetRegion          = "Broker.GetRegion"
+	SetCells           = "Broker.SetCells"
+	ClearRegion        = "Broker.ClearRegion"
+	PastePattern       = "Broker.PastePattern"
+	Step               = "Broker.Step"
+	SetSpeed           = "Broker.SetSpeed"
 	CalculateNextState = "GolWorker.CalculateNextState"
+	WorkerHello        = "GolWorker.Hello"
 )
 
+// Every request to the broker carries a Token, which must grant the role the
+// method needs when the broker was started with tokens; see auth.go.
+
+// HelloRequest opens every connection; see hello.go. It carries the caller's
+// ProtocolVersion, the rules it will ask for, the encodings it can send and
+// receive, preferred first, the largest world it accepts in cells (0 for no
+// limit) and the features it has.
+type HelloRequest struct {
+	Version   int
+	Rules     []string
+	Encodings []string
+	MaxCells  int
+	Features  []string
+	Token     string
+}
+
+// HelloResponse describes the callee in the same terms. Encoding is the one it
+// picked from HelloRequest.Encodings, which both sides then use for cells;
+// EncodingRaw if they share none. A broker also reports the Role the token
+// was granted.
+type HelloResponse struct {
+	Version  int
+	Rules    []string
+	Encoding string
+	MaxCells int
+	Features []string
+	Role     string
+}
+
+// EngineRequest starts a run. When Probability is strictly between 0 and 1,
+// each transition the rules call for only happens with that probability,
+// drawn from a random stream keyed by Seed, the turn and the cell. When
+// Encoding is set, World is nil and Cells holds it flattened and encoded. The
+// run ends early once one of Until holds.
 type EngineRequest struct {
 	World       [][]uint8
 	ImageWidth  int
 	ImageHeight int
 	Turns       int
+	Threads     int
+	Seed        uint64
+	Probability float64
+	Encoding    string
+	Cells       []byte
+	Token       string
+	Until       StopConditions
 }
 
+// EngineResponse numbers the run that was started, for SubscribeRequest.Run.
 type EngineResponse struct {
 	World          [][]uint8
 	CompletedTurns int
+	Run            int
 }
 
-type AliveCellsCountRequest struct{}
+type AliveCellsCountRequest struct {
+	Token string
+}
 
 type AliveCellsCountResponse struct {
 	CompletedTurns int
 	CellsCount     int
 }
 
-type StopRequest struct{}
+type StopRequest struct {
+	Token string
+}
 
 type StopResponse struct{}
 
-type GetWorldRequest struct{}
+// GetWorldRequest asks for the world in Encoding. The response then carries
+// it flattened in Cells rather than in World.
+type GetWorldRequest struct {
+	Encoding string
+	Token    string
+}
 
 type GetWorldResponse struct {
 	World          [][]uint8
 	CompletedTurns int
 	Processing     bool
+	Encoding       string
+	Cells          []byte
+	ImageWidth     int
+	ImageHeight    int
 }
 
-type PauseRequest struct{}
+// GetWorldDeltaRequest asks for what changed since the client's copy of the
+// world, taken at SinceTurn of run Run after SinceEdits edits, with Cells in
+// Encoding.
+type GetWorldDeltaRequest struct {
+	Run        int
+	SinceTurn  int
+	SinceEdits int
+	Encoding   string
+	Token      string
+}
+
+// GetWorldDeltaResponse brings a copy up to CompletedTurns of Run. When Full is
+// set, Cells holds the whole world flattened, because the client's copy is from
+// another run; otherwise it holds the current cells of the listed Tiles, each
+// TileSize square clipped to the world, one after another and row by row.
+// Tiles are numbered row by row. Edits counts the edits made to Run so far,
+// which can change the world without completing a turn. Use ApplyWorldDelta to
+// update a copy.
+type GetWorldDeltaResponse struct {
+	Run            int
+	CompletedTurns int
+	Edits          int
+	Processing     bool
+	ImageWidth     int
+	ImageHeight    int
+	TileSize       int
+	Full           bool
+	Tiles          []int
+	Encoding       string
+	Cells          []byte
+}
+
+// GetRegionRequest asks for the Width by Height rectangle of the world whose top
+// left cell is (X, Y), shrunk by Scale in each direction, with Cells in
+// Encoding. The rectangle is clipped to the world.
+type GetRegionRequest struct {
+	X        int
+	Y        int
+	Width    int
+	Height   int
+	Scale    int
+	Encoding string
+	Token    string
+}
+
+// GetRegionResponse holds the clipped rectangle, X, Y, Width and Height, as
+// ceil(Width/Scale) by ceil(Height/Scale) values flattened row by row into
+// Cells. With Scale 1 the values are cells; otherwise each is the share of
+// alive cells in a Scale by Scale tile, from 0 to 255. EncodingRLE only keeps
+// whether a value is zero, so density tiles are never sent with it.
+type GetRegionResponse struct {
+	CompletedTurns int
+	ImageWidth     int
+	ImageHeight    int
+	X              int
+	Y              int
+	Width          int
+	Height         int
+	Scale          int
+	Encoding       string
+	Cells          []byte
+}
+
+// Edits change the world between turns: one that arrives during a turn waits
+// for it to complete, so workers never see half of it.
+
+// SetCellsRequest makes every listed cell alive, or dead if Dead is set.
+type SetCellsRequest struct {
+	Cells []util.Cell
+	Dead  bool
+	Token string
+}
+
+// ClearRegionRequest kills every cell in the Width by Height rectangle whose
+// top left cell is (X, Y), clipped to the world.
+type ClearRegionRequest struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+	Token  string
+}
+
+// PastePatternRequest copies the Width by Height Cells, flattened row by row,
+// onto the world with their top left cell at (X, Y), wrapping around the edges
+// like the world does. Any non-zero cell is alive. When Merge is set only the
+// alive cells of the pattern are copied.
+type PastePatternRequest struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+	Cells  []uint8
+	Merge  bool
+	Token  string
+}
+
+// EditResponse answers every edit with the number of completed turns when it
+// took effect and the number of cells it flipped. The flips are also sent to
+// subscribers as an EventCellsFlipped.
+type EditResponse struct {
+	Turn    int
+	Flipped int
+}
+
+type PauseRequest struct {
+	Token string
+}
 
 type PauseResponse struct {
 	Turn int
 }
 
-type ResumeRequest struct{}
+// StepRequest asks a paused run to complete Turns more turns and pause again.
+type StepRequest struct {
+	Turns int
+	Token string
+}
+
+// StepResponse holds the turn the run will pause at. The run's events report
+// it executing and, once it gets there, paused.
+type StepResponse struct {
+	Turn int
+}
+
+// SetSpeedRequest caps the current run at TurnsPerSecond turns a second, or
+// lifts the cap if it is 0. Every run starts without a cap.
+type SetSpeedRequest struct {
+	TurnsPerSecond float64
+	Token          string
+}
+
+type SetSpeedResponse struct{}
+
+// MinSpeed is the lowest speed SetSpeed accepts other than 0.
+const MinSpeed = 0.001
+
+type ResumeRequest struct {
+	Token string
+}
 
 type ResumeResponse struct{}
 
-type ShutdownRequest struct{}
+type ShutdownRequest struct {
+	Token string
+}
 
 type ShutdownResponse struct{}
 
+// WorkerRequest carries the block [StartX, EndX) x [StartY, EndY) surrounded
+// by a one-cell halo of ghost rows, columns and corners, flattened row by row
+// into WorldSlice, which the worker splits across Threads goroutines. Dirty
+// holds one flag per tile overlapping the block, row by row; a worker may copy
+// clean tiles through unchanged. A nil Dirty means every tile is dirty. Turn,
+// Seed and Probability select the random draws of a stochastic run, as
+// described on EngineRequest.
 type WorkerRequest struct {
+	StartX      int
+	EndX        int
 	StartY      int
 	EndY        int
-	WorldSlice  [][]uint8
+	WorldSlice  []uint8
 	ImageWidth  int
 	ImageHeight int
+	Threads     int
+	TileSize    int
+	Dirty       []bool
+	Turn        int
+	Seed        uint64
+	Probability float64
 }
 
+// WorkerResponse returns the next state of the block, flattened and without
+// its halo, and, in the same layout as WorkerRequest.Dirty, which tiles changed
+// during this turn.
 type WorkerResponse struct {
-	WorldSlice [][]uint8
+	WorldSlice []uint8
+	Changed    []bool
 }
@@ -0,0 +1,59 @@
+package stubs
+
+import (
+	"fmt"
+	"net/rpc"
+	"time"
+)
+
+// DefaultTimeout is how long the controller and the broker wait for a call or
+// a connection before giving up on the other end.
+const DefaultTimeout = 30 * time.Second
+
+// Caller starts calls asynchronously. Both *rpc.Client and *GRPCClient
+// implement it.
+type Caller interface {
+	Go(serviceMethod string, args, reply interface{}, done chan *rpc.Call) *rpc.Call
+}
+
+// TimeoutError is returned by CallTimeout when the callee does not answer in time.
+type TimeoutError struct {
+	Method string
+	After  time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v", e.Method, e.After)
+}
+
+// Timeout reports true, as net.Error does for timeouts.
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// CallTimeout calls serviceMethod like rpc.Client.Call but gives up with a
+// *TimeoutError after timeout, or waits for ever if timeout is 0. A call that
+// timed out may still write to reply later, so reply must not be read or reused.
+func CallTimeout(c Caller, timeout time.Duration, serviceMethod string, args, reply interface{}) error {
+	call := c.Go(serviceMethod, args, reply, make(chan *rpc.Call, 1))
+	if timeout <= 0 {
+		<-call.Done
+		return call.Error
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-timer.C:
+		return &TimeoutError{Method: serviceMethod, After: timeout}
+	}
+}
+
+// CallerTimeout returns a Call function for c that gives up after timeout, for
+// use with SayHello.
+func CallerTimeout(c Caller, timeout time.Duration) func(serviceMethod string, args, reply interface{}) error {
+	return func(serviceMethod string, args, reply interface{}) error {
+		return CallTimeout(c, timeout, serviceMethod, args, reply)
+	}
+}
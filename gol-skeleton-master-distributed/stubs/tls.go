@@ -0,0 +1,200 @@
+package stubs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Every link can run over TLS with mutual authentication: each node presents a
+// certificate signed by a shared CA and only accepts peers whose certificates
+// that CA signed. GenerateCerts makes such a CA and node certificates for tests
+// and small clusters; the gencerts command wraps it.
+
+// TLSFiles names the PEM files a node needs for TLS: its own certificate and
+// key, and the CA certificate that signs every node. TLS is off when all three
+// are empty.
+type TLSFiles struct {
+	Cert string
+	Key  string
+	CA   string
+}
+
+// Enabled reports whether any of the files is set.
+func (f TLSFiles) Enabled() bool {
+	return f.Cert != "" || f.Key != "" || f.CA != ""
+}
+
+func (f TLSFiles) load() (tls.Certificate, *x509.CertPool, error) {
+	if f.Cert == "" || f.Key == "" || f.CA == "" {
+		return tls.Certificate{}, nil, errors.New("TLS needs a certificate, a key and a CA certificate")
+	}
+	cert, err := tls.LoadX509KeyPair(f.Cert, f.Key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	caPEM, err := os.ReadFile(f.CA)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return tls.Certificate{}, nil, fmt.Errorf("no certificates in %s", f.CA)
+	}
+	return cert, pool, nil
+}
+
+// ServerConfig returns the TLS configuration of a listener, which demands a
+// client certificate signed by the CA. It returns nil if TLS is off.
+func (f TLSFiles) ServerConfig() (*tls.Config, error) {
+	if !f.Enabled() {
+		return nil, nil
+	}
+	cert, pool, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientConfig returns the TLS configuration of a dialer, which presents the
+// node's certificate and checks the server's against the CA. It returns nil if
+// TLS is off.
+func (f TLSFiles) ClientConfig() (*tls.Config, error) {
+	if !f.Enabled() {
+		return nil, nil
+	}
+	cert, pool, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// Listen listens on addr, over TLS when config is not nil.
+func Listen(addr string, config *tls.Config) (net.Listener, error) {
+	if config != nil {
+		return tls.Listen("tcp", addr, config)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// Dial connects to addr, over TLS when config is not nil, giving up after
+// timeout unless it is 0.
+func Dial(addr string, config *tls.Config, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	if config != nil {
+		return tls.DialWithDialer(dialer, "tcp", addr, config)
+	}
+	return dialer.Dial("tcp", addr)
+}
+
+// GenerateCerts writes a new self-signed CA to dir as ca.pem and ca-key.pem,
+// and for each of nodes a certificate and key signed by it, as <node>.pem and
+// <node>-key.pem. Node certificates are valid for both ends of a connection
+// and for the given host names and IP addresses.
+func GenerateCerts(dir string, hosts, nodes []string, validFor time.Duration) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	// Backdate certificates a little to allow for clock skew between nodes.
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(validFor)
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Game of Life CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return err
+	}
+	if err := writeKeyPair(dir, "ca", caDER, caKey); err != nil {
+		return err
+	}
+
+	for i, node := range nodes {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return err
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i) + 2),
+			Subject:      pkix.Name{CommonName: node},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		for _, host := range hosts {
+			if ip := net.ParseIP(host); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else {
+				template.DNSNames = append(template.DNSNames, host)
+			}
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		if err != nil {
+			return err
+		}
+		if err := writeKeyPair(dir, node, der, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeKeyPair writes a certificate to <name>.pem and its key to <name>-key.pem.
+func writeKeyPair(dir, name string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, name+".pem"), certPEM, 0o644); err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return os.WriteFile(filepath.Join(dir, name+"-key.pem"), keyPEM, 0o600)
+}
+
+// NodeTLSFiles returns the files GenerateCerts wrote to dir for node.
+func NodeTLSFiles(dir, node string) TLSFiles {
+	return TLSFiles{
+		Cert: filepath.Join(dir, node+".pem"),
+		Key:  filepath.Join(dir, node+"-key.pem"),
+		CA:   filepath.Join(dir, "ca.pem"),
+	}
+}
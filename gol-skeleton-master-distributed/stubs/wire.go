@@ -0,0 +1,409 @@
+package stubs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"math"
+	"net/rpc"
+	"sync"
+)
+
+// Broker-to-worker connections carry net/rpc calls in length-prefixed frames
+// instead of a gob stream, so that both ends can encode from and decode into
+// buffers they reuse turn after turn. WorkerRequest and WorkerResponse bodies
+// are packed by hand, with their cells in the encoding set on the FrameWriter;
+// anything else falls back to a self-contained gob message.
+
+const (
+	frameGob    = 0
+	frameWorker = 1
+)
+
+// maxSlice bounds the cells in one block's slice: a block of up to MaxCells
+// cells and its halo, which is largest for a block one cell high.
+const maxSlice = 3*MaxCells + 6
+
+// maxFrame bounds a single frame: a slice's cells, allowing for DEFLATE's
+// stored blocks outgrowing them, a flag for each tile, of which there are no
+// more than cells, and the call header.
+const maxFrame = maxSlice + maxSlice/1024 + maxSlice + 1<<16
+
+var errShortFrame = errors.New("stubs: truncated frame")
+
+// FrameWriter writes frames to a buffered connection, reusing its scratch buffers.
+type FrameWriter struct {
+	w        *bufio.Writer
+	scratch  []byte
+	cells    []byte
+	encoding string
+	comp     Compressor
+	stats    *WireStats
+}
+
+// NewFrameWriter returns a FrameWriter on w.
+func NewFrameWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: bufio.NewWriter(w)}
+}
+
+// SetEncoding sets the encoding of the cells in later WorkerRequest and
+// WorkerResponse bodies. The peer must have agreed to it.
+func (f *FrameWriter) SetEncoding(enc string) {
+	f.encoding = enc
+}
+
+// SetStats makes f count the cell bytes it writes in s.
+func (f *FrameWriter) SetStats(s *WireStats) {
+	f.stats = s
+}
+
+// WriteFrame writes the call header followed by body, then flushes.
+func (f *FrameWriter) WriteFrame(method string, seq uint64, errMsg string, body interface{}) error {
+	buf := f.scratch[:0]
+	buf = appendString(buf, method)
+	buf = binary.AppendUvarint(buf, seq)
+	buf = appendString(buf, errMsg)
+	var err error
+	switch b := body.(type) {
+	case *WorkerRequest:
+		buf = append(buf, frameWorker)
+		buf, err = f.appendWorkerRequest(buf, b)
+	case *WorkerResponse:
+		buf = append(buf, frameWorker)
+		buf, err = f.appendWorkerResponse(buf, b)
+	default:
+		buf = append(buf, frameGob)
+		var gobBuf bytes.Buffer
+		if err := gob.NewEncoder(&gobBuf).Encode(body); err != nil {
+			return err
+		}
+		buf = append(buf, gobBuf.Bytes()...)
+	}
+	f.scratch = buf
+	if err != nil {
+		return err
+	}
+
+	var length [binary.MaxVarintLen64]byte
+	if _, err := f.w.Write(length[:binary.PutUvarint(length[:], uint64(len(buf)))]); err != nil {
+		return err
+	}
+	if _, err := f.w.Write(buf); err != nil {
+		return err
+	}
+	return f.w.Flush()
+}
+
+// FrameReader reads frames from a connection into one reusable buffer.
+type FrameReader struct {
+	r        *bufio.Reader
+	limit    io.LimitedReader
+	frame    bytes.Buffer
+	body     []byte
+	encoding string
+	comp     Compressor
+	stats    *WireStats
+}
+
+// NewFrameReader returns a FrameReader on r.
+func NewFrameReader(r io.Reader) *FrameReader {
+	return &FrameReader{r: bufio.NewReader(r)}
+}
+
+// SetStats makes f count the cell bytes it reads in s.
+func (f *FrameReader) SetStats(s *WireStats) {
+	f.stats = s
+}
+
+// Encoding returns the encoding of the cells in the last body read.
+func (f *FrameReader) Encoding() string {
+	return f.encoding
+}
+
+// ReadHeader reads the next frame and returns its call header. The body stays
+// buffered until ReadBody is called.
+func (f *FrameReader) ReadHeader() (method string, seq uint64, errMsg string, err error) {
+	length, err := binary.ReadUvarint(f.r)
+	if err != nil {
+		return "", 0, "", err
+	}
+	if length > maxFrame {
+		return "", 0, "", errors.New("stubs: frame too large")
+	}
+	// The buffer grows only as the bytes arrive, so a length header alone
+	// cannot make the reader allocate.
+	f.frame.Reset()
+	f.limit = io.LimitedReader{R: f.r, N: int64(length)}
+	if _, err = f.frame.ReadFrom(&f.limit); err != nil {
+		return "", 0, "", err
+	}
+	if uint64(f.frame.Len()) < length {
+		return "", 0, "", io.ErrUnexpectedEOF
+	}
+	d := decoder{data: f.frame.Bytes()}
+	method = d.string()
+	seq = d.uvarint()
+	errMsg = d.string()
+	if d.err != nil {
+		return "", 0, "", d.err
+	}
+	f.body = d.data
+	return method, seq, errMsg, nil
+}
+
+// ReadBody decodes the body of the last frame into body, reusing the capacity
+// of any slices it already holds. A nil body discards it.
+func (f *FrameReader) ReadBody(body interface{}) error {
+	data := f.body
+	f.body = nil
+	if body == nil {
+		return nil
+	}
+	if len(data) == 0 {
+		return errShortFrame
+	}
+	kind, data := data[0], data[1:]
+	if kind == frameGob {
+		return gob.NewDecoder(bytes.NewReader(data)).Decode(body)
+	}
+	d := decoder{data: data, comp: &f.comp}
+	switch b := body.(type) {
+	case *WorkerRequest:
+		d.workerRequest(b)
+	case *WorkerResponse:
+		d.workerResponse(b)
+	default:
+		return errors.New("stubs: unexpected worker frame body")
+	}
+	if d.err == nil {
+		f.encoding = d.encoding
+		if f.stats != nil {
+			f.stats.add(d.raw, d.wire)
+		}
+	}
+	return d.err
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendInt(buf []byte, v int) []byte {
+	return binary.AppendVarint(buf, int64(v))
+}
+
+func appendBytes(buf, b []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+// appendFlags packs flags one per byte, storing nil as length 0 and a slice of
+// length n as n+1 so that the two stay distinct.
+func appendFlags(buf []byte, flags []bool) []byte {
+	if flags == nil {
+		return binary.AppendUvarint(buf, 0)
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(flags))+1)
+	for _, flag := range flags {
+		if flag {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+	}
+	return buf
+}
+
+// appendCells writes cells as their encoding, their count and the encoded bytes.
+func (f *FrameWriter) appendCells(buf []byte, cells []uint8) ([]byte, error) {
+	var err error
+	f.cells, err = f.comp.Encode(f.cells[:0], f.encoding, cells)
+	if err != nil {
+		return buf, err
+	}
+	if f.stats != nil {
+		f.stats.add(len(cells), len(f.cells))
+	}
+	buf = appendString(buf, f.encoding)
+	buf = binary.AppendUvarint(buf, uint64(len(cells)))
+	return appendBytes(buf, f.cells), nil
+}
+
+func (f *FrameWriter) appendWorkerRequest(buf []byte, req *WorkerRequest) ([]byte, error) {
+	for _, v := range [...]int{req.StartX, req.EndX, req.StartY, req.EndY, req.ImageWidth, req.ImageHeight, req.Threads, req.TileSize, req.Turn} {
+		buf = appendInt(buf, v)
+	}
+	buf = binary.AppendUvarint(buf, req.Seed)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(req.Probability))
+	buf, err := f.appendCells(buf, req.WorldSlice)
+	return appendFlags(buf, req.Dirty), err
+}
+
+func (f *FrameWriter) appendWorkerResponse(buf []byte, res *WorkerResponse) ([]byte, error) {
+	buf, err := f.appendCells(buf, res.WorldSlice)
+	return appendFlags(buf, res.Changed), err
+}
+
+// decoder reads the packed fields of a frame, remembering the first error. It
+// also records the encoding and sizes of the cells it decodes.
+type decoder struct {
+	data     []byte
+	err      error
+	comp     *Compressor
+	encoding string
+	raw      int
+	wire     int
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errShortFrame
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) int() int {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errShortFrame
+		return 0
+	}
+	d.data = d.data[n:]
+	return int(v)
+}
+
+func (d *decoder) next(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if uint64(len(d.data)) < n {
+		d.err = errShortFrame
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) string() string {
+	return string(d.next(d.uvarint()))
+}
+
+// cells decodes cells written by appendCells into dst, growing it only if needed.
+func (d *decoder) cells(dst []uint8) []uint8 {
+	enc, err := encodingName(d.next(d.uvarint()))
+	n := d.uvarint()
+	data := d.next(d.uvarint())
+	if d.err != nil {
+		return dst
+	}
+	if err != nil {
+		d.err = err
+		return dst
+	}
+	if n > maxSlice {
+		d.err = errors.New("stubs: block too large")
+		return dst
+	}
+	dst, d.err = d.comp.Decode(dst, enc, data, int(n))
+	d.encoding = enc
+	d.raw += int(n)
+	d.wire += len(data)
+	return dst
+}
+
+func (d *decoder) flags(dst []bool) []bool {
+	n := d.uvarint()
+	if n == 0 {
+		return nil
+	}
+	src := d.next(n - 1)
+	if cap(dst) < len(src) {
+		dst = make([]bool, len(src))
+	}
+	dst = dst[:len(src)]
+	for i, b := range src {
+		dst[i] = b != 0
+	}
+	return dst
+}
+
+func (d *decoder) workerRequest(req *WorkerRequest) {
+	for _, v := range [...]*int{&req.StartX, &req.EndX, &req.StartY, &req.EndY, &req.ImageWidth, &req.ImageHeight, &req.Threads, &req.TileSize, &req.Turn} {
+		*v = d.int()
+	}
+	req.Seed = d.uvarint()
+	if bits := d.next(8); bits != nil {
+		req.Probability = math.Float64frombits(binary.LittleEndian.Uint64(bits))
+	}
+	req.WorldSlice = d.cells(req.WorldSlice)
+	req.Dirty = d.flags(req.Dirty)
+}
+
+func (d *decoder) workerResponse(res *WorkerResponse) {
+	res.WorldSlice = d.cells(res.WorldSlice)
+	res.Changed = d.flags(res.Changed)
+}
+
+// WorkerClientCodec is the client half of the framed worker protocol.
+type WorkerClientCodec struct {
+	rwc   io.ReadWriteCloser
+	r     *FrameReader
+	w     *FrameWriter
+	mu    sync.Mutex
+	stats WireStats
+}
+
+// NewWorkerClientCodec returns an rpc.ClientCodec speaking the framed worker
+// protocol on conn. Use it with rpc.NewClientWithCodec.
+func NewWorkerClientCodec(conn io.ReadWriteCloser) *WorkerClientCodec {
+	c := &WorkerClientCodec{rwc: conn, r: NewFrameReader(conn), w: NewFrameWriter(conn)}
+	c.r.SetStats(&c.stats)
+	c.w.SetStats(&c.stats)
+	return c
+}
+
+// SetEncoding sets the encoding of the cells in later requests, once the
+// worker has agreed to it in Hello. The worker answers in kind.
+func (c *WorkerClientCodec) SetEncoding(enc string) {
+	c.mu.Lock()
+	c.w.SetEncoding(enc)
+	c.mu.Unlock()
+}
+
+// Stats returns the cell bytes sent and received so far.
+func (c *WorkerClientCodec) Stats() *WireStats {
+	return &c.stats
+}
+
+func (c *WorkerClientCodec) WriteRequest(r *rpc.Request, body interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.w.WriteFrame(r.ServiceMethod, r.Seq, "", body)
+}
+
+func (c *WorkerClientCodec) ReadResponseHeader(r *rpc.Response) (err error) {
+	r.ServiceMethod, r.Seq, r.Error, err = c.r.ReadHeader()
+	return err
+}
+
+func (c *WorkerClientCodec) ReadResponseBody(body interface{}) error {
+	return c.r.ReadBody(body)
+}
+
+func (c *WorkerClientCodec) Close() error {
+	return c.rwc.Close()
+}
@@ -0,0 +1,45 @@
+package stubs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"runtime"
+	"testing"
+)
+
+// TestFrameLimits sends frames whose headers claim far more than they carry,
+// and checks that they are refused without the reader allocating what they claim.
+func TestFrameLimits(t *testing.T) {
+	frame := func(length uint64, body []byte) io.Reader {
+		return bytes.NewReader(append(binary.AppendUvarint(nil, length), body...))
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	if _, _, _, err := NewFrameReader(frame(maxFrame+1, nil)).ReadHeader(); err == nil {
+		t.Error("expected a frame longer than maxFrame to be refused")
+	}
+	if _, _, _, err := NewFrameReader(frame(maxFrame, []byte("short"))).ReadHeader(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected a truncated frame to be refused with io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	var c Compressor
+	for _, test := range []struct {
+		name string
+		body []byte
+	}{
+		{"too many cells", appendBytes(binary.AppendUvarint(appendString(nil, EncodingRLE), maxSlice+1), []byte{1})},
+		{"raw cells missing", appendBytes(binary.AppendUvarint(appendString(nil, EncodingRaw), 1<<30), []byte{1})},
+	} {
+		d := decoder{data: test.body, comp: &c}
+		if d.cells(nil); d.err == nil {
+			t.Errorf("%s: expected the cells to be refused", test.name)
+		}
+	}
+
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("refusing the frames allocated %d bytes", allocated)
+	}
+}
@@ -0,0 +1,18 @@
+package util
+
+// splitMix64 is the SplitMix64 finaliser, used to mix counters into random bits.
+func splitMix64(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// CellRandom returns a uniform value in [0, 1) that depends only on the seed, the
+// turn and the global cell coordinates, so every partition layout draws the same value.
+func CellRandom(seed uint64, turn, x, y int) float64 {
+	z := splitMix64(seed ^ uint64(turn))
+	z = splitMix64(z ^ uint64(y))
+	z = splitMix64(z ^ uint64(x))
+	return float64(z>>11) / (1 << 53)
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"io"
+	"net/rpc"
+	"sync"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+)
+
+// workerCodec is the server half of the framed worker protocol. It lends pooled
+// buffers to WorkerRequest bodies so that they decode into existing memory, and
+// takes the buffers of a request and its response back once the response has
+// been written. Each response is encoded like the request it answers.
+type workerCodec struct {
+	rwc    io.ReadWriteCloser
+	r      *stubs.FrameReader
+	w      *stubs.FrameWriter
+	worker *GolWorker
+	seq    uint64
+
+	mu      sync.Mutex
+	pending map[uint64]pendingRequest
+}
+
+// pendingRequest is what the codec remembers about a request until it has
+// written the response.
+type pendingRequest struct {
+	cells    []uint8
+	encoding string
+}
+
+// NewServerCodec serves worker on conn with the framed worker protocol, the
+// counterpart of stubs.NewWorkerClientCodec.
+func NewServerCodec(conn io.ReadWriteCloser, worker *GolWorker) rpc.ServerCodec {
+	return &workerCodec{
+		rwc:     conn,
+		r:       stubs.NewFrameReader(conn),
+		w:       stubs.NewFrameWriter(conn),
+		worker:  worker,
+		pending: make(map[uint64]pendingRequest),
+	}
+}
+
+func (c *workerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	r.ServiceMethod, r.Seq, _, err = c.r.ReadHeader()
+	c.seq = r.Seq
+	return err
+}
+
+func (c *workerCodec) ReadRequestBody(body interface{}) error {
+	req, ok := body.(*stubs.WorkerRequest)
+	if !ok {
+		return c.r.ReadBody(body)
+	}
+	req.WorldSlice = c.worker.getCells(0)
+	if err := c.r.ReadBody(req); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.pending[c.seq] = pendingRequest{cells: req.WorldSlice, encoding: c.r.Encoding()}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *workerCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	c.mu.Lock()
+	pending := c.pending[r.Seq]
+	delete(c.pending, r.Seq)
+	c.mu.Unlock()
+
+	c.w.SetEncoding(pending.encoding)
+	err := c.w.WriteFrame(r.ServiceMethod, r.Seq, r.Error, body)
+	c.worker.putCells(pending.cells)
+	if res, ok := body.(*stubs.WorkerResponse); ok {
+		c.worker.putCells(res.WorldSlice)
+		c.worker.putFlags(res.Changed)
+	}
+	return err
+}
+
+func (c *workerCodec) Close() error {
+	return c.rwc.Close()
+}
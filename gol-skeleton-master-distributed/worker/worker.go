@@ -0,0 +1,229 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+	"uk.ac.bris.cs/gameoflife/util"
+)
+
+// GolWorker holds no simulation state between requests, so concurrent calls
+// from several brokers or sessions run in parallel. It only keeps free lists of
+// buffers so that a steady stream of turns does not allocate.
+type GolWorker struct {
+	mu    sync.Mutex
+	cells [][]uint8
+	flags [][]bool
+}
+
+// getCells returns a reusable cell buffer of length n.
+func (g *GolWorker) getCells(n int) []uint8 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, buf := range g.cells {
+		if cap(buf) >= n {
+			g.cells[i] = g.cells[len(g.cells)-1]
+			g.cells = g.cells[:len(g.cells)-1]
+			return buf[:n]
+		}
+	}
+	return make([]uint8, n)
+}
+
+// putCells hands a cell buffer back for reuse.
+func (g *GolWorker) putCells(buf []uint8) {
+	if cap(buf) == 0 {
+		return
+	}
+	g.mu.Lock()
+	g.cells = append(g.cells, buf[:0])
+	g.mu.Unlock()
+}
+
+// getFlags returns a reusable tile flag buffer of length n, cleared to false.
+func (g *GolWorker) getFlags(n int) []bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, buf := range g.flags {
+		if cap(buf) >= n {
+			g.flags[i] = g.flags[len(g.flags)-1]
+			g.flags = g.flags[:len(g.flags)-1]
+			buf = buf[:n]
+			for j := range buf {
+				buf[j] = false
+			}
+			return buf
+		}
+	}
+	return make([]bool, n)
+}
+
+// putFlags hands a tile flag buffer back for reuse.
+func (g *GolWorker) putFlags(buf []bool) {
+	if cap(buf) == 0 {
+		return
+	}
+	g.mu.Lock()
+	g.flags = append(g.flags, buf[:0])
+	g.mu.Unlock()
+}
+
+// calculateNeighbours counts the live neighbours of cell i in a flat slice with
+// the given stride. The slice has a halo, so no wrapping is needed.
+func calculateNeighbours(world []uint8, i, stride int) int {
+	count := 0
+	for _, j := range [8]int{i - stride - 1, i - stride, i - stride + 1, i - 1, i + 1, i + stride - 1, i + stride, i + stride + 1} {
+		if world[j] == 255 {
+			count++
+		}
+	}
+	return count
+}
+
+// nextCellState applies the Game of Life rules to cell i of the flat slice.
+func nextCellState(world []uint8, i, stride int) uint8 {
+	neighbours := calculateNeighbours(world, i, stride)
+	if world[i] == 255 {
+		if neighbours == 2 || neighbours == 3 {
+			return 255
+		}
+		return 0
+	}
+	if neighbours == 3 {
+		return 255
+	}
+	return 0
+}
+
+// calculateRows computes rows [startRow, endRow) of the block into the flat
+// newWorldSlice, copying clean tiles through and flagging the tiles that
+// changed. The slice carries a one-cell halo on every side, so block cell
+// (x, y-1) is slice cell (x+1, y).
+func calculateRows(req *stubs.WorkerRequest, tileSize, startRow, endRow int, newWorldSlice []uint8, changed []bool) {
+	worldSlice := req.WorldSlice
+	blockWidth := req.EndX - req.StartX
+	stride := blockWidth + 2
+	firstTileX := req.StartX / tileSize
+	tilesX := (req.EndX-1)/tileSize - firstTileX + 1
+	firstTileY := req.StartY / tileSize
+	stochastic := req.Probability > 0 && req.Probability < 1
+
+	for y := startRow; y < endRow; y++ {
+		in := y*stride + 1
+		out := (y - 1) * blockWidth
+		tileRow := (req.StartY+y-1)/tileSize - firstTileY
+		for tileX := 0; tileX < tilesX; tileX++ {
+			tile := tileRow*tilesX + tileX
+			startX := (firstTileX+tileX)*tileSize - req.StartX
+			if startX < 0 {
+				startX = 0
+			}
+			endX := (firstTileX+tileX+1)*tileSize - req.StartX
+			if endX > blockWidth {
+				endX = blockWidth
+			}
+			if req.Dirty != nil && !req.Dirty[tile] {
+				// Nothing nearby changed last turn, so nothing here can change now.
+				copy(newWorldSlice[out+startX:out+endX], worldSlice[in+startX:in+endX])
+				continue
+			}
+			for x := startX; x < endX; x++ {
+				cell := nextCellState(worldSlice, in+x, stride)
+				newWorldSlice[out+x] = cell
+				if cell == worldSlice[in+x] {
+					continue
+				}
+				// A suppressed transition still marks the tile, so it is retried next turn.
+				changed[tile] = true
+				if stochastic && util.CellRandom(req.Seed, req.Turn, req.StartX+x, req.StartY+y-1) >= req.Probability {
+					newWorldSlice[out+x] = worldSlice[in+x]
+				}
+			}
+		}
+	}
+}
+
+// Hello checks that the broker speaks this worker's protocol and picks the
+// encoding it should send cells in. Responses are encoded the same way as the
+// request they answer, so the worker keeps no per-connection state.
+func (g *GolWorker) Hello(req *stubs.HelloRequest, res *stubs.HelloResponse) error {
+	if err := stubs.CheckHello("broker", req); err != nil {
+		return err
+	}
+	*res = stubs.HelloResponse{
+		Version:  stubs.ProtocolVersion,
+		Rules:    stubs.Rules,
+		Encoding: stubs.ChooseEncoding(req.Encodings, stubs.Encodings),
+		MaxCells: stubs.MaxCells,
+		Features: []string{stubs.FeatureNoisy, stubs.FeatureDirtyTiles},
+	}
+	return nil
+}
+
+func (g *GolWorker) CalculateNextState(req *stubs.WorkerRequest, res *stubs.WorkerResponse) error {
+	rows := req.EndY - req.StartY
+	blockWidth := req.EndX - req.StartX
+	if rows <= 0 || blockWidth <= 0 || req.StartX < 0 || req.StartY < 0 {
+		return fmt.Errorf("block [%v, %v) x [%v, %v) is empty", req.StartX, req.EndX, req.StartY, req.EndY)
+	}
+	if len(req.WorldSlice) != (rows+2)*(blockWidth+2) {
+		return fmt.Errorf("world slice has %v cells, expected %v", len(req.WorldSlice), (rows+2)*(blockWidth+2))
+	}
+
+	// Without a tile size the whole block is treated as a single dirty tile.
+	tileSize := req.TileSize
+	if tileSize <= 0 {
+		tileSize = req.ImageWidth + req.ImageHeight
+	}
+	tilesX := (req.EndX-1)/tileSize - req.StartX/tileSize + 1
+	tileRows := (req.EndY-1)/tileSize - req.StartY/tileSize + 1
+	if req.Dirty != nil && len(req.Dirty) != tilesX*tileRows {
+		return fmt.Errorf("dirty has %v flags, expected %v", len(req.Dirty), tilesX*tileRows)
+	}
+
+	threads := req.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	if threads > rows {
+		threads = rows
+	}
+
+	// Each goroutine owns a band of rows and its own changed flags, since
+	// neighbouring bands may fall in the same tile row.
+	newWorldSlice := g.getCells(rows * blockWidth)
+	changed := g.getFlags(tileRows * tilesX)
+	rowsPerThread := rows / threads
+	remainder := rows % threads
+	var wg sync.WaitGroup
+	wg.Add(threads)
+	startRow := 1
+	for i := 0; i < threads; i++ {
+		endRow := startRow + rowsPerThread
+		if i < remainder {
+			endRow++
+		}
+		go func(startRow, endRow int) {
+			defer wg.Done()
+			part := g.getFlags(len(changed))
+			calculateRows(req, tileSize, startRow, endRow, newWorldSlice, part)
+			g.mu.Lock()
+			for tile, c := range part {
+				if c {
+					changed[tile] = true
+				}
+			}
+			g.mu.Unlock()
+			g.putFlags(part)
+		}(startRow, endRow)
+		startRow = endRow
+	}
+	wg.Wait()
+
+	// The codec returns these buffers, and the request's, to the free lists once
+	// the response is written.
+	res.WorldSlice = newWorldSlice
+	res.Changed = changed
+	return nil
+}
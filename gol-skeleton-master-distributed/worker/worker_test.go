@@ -0,0 +1,249 @@
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"uk.ac.bris.cs/gameoflife/stubs"
+)
+
+// readWorld loads a square pgm image from the images folder.
+func readWorld(tb testing.TB, size int) [][]uint8 {
+	data, err := os.ReadFile(fmt.Sprintf("../images/%vx%v.pgm", size, size))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	image := []byte(strings.Fields(string(data))[4])
+	world := make([][]uint8, size)
+	for y := range world {
+		world[y] = append([]uint8(nil), image[y*size:(y+1)*size]...)
+	}
+	return world
+}
+
+// stepBlocks advances the world by one turn, cutting it into rows x cols blocks
+// with halos the way the broker does and calling the worker on each block.
+func stepBlocks(t *testing.T, world [][]uint8, rows, cols, turn int, seed uint64, probability float64) [][]uint8 {
+	height, width := len(world), len(world[0])
+	newWorld := make([][]uint8, height)
+	for y := range newWorld {
+		newWorld[y] = make([]uint8, width)
+	}
+	worker := new(GolWorker)
+	for i := 0; i < rows*cols; i++ {
+		startY, endY := i/cols*height/rows, (i/cols+1)*height/rows
+		startX, endX := i%cols*width/cols, (i%cols+1)*width/cols
+		slice := make([]uint8, 0, (endY-startY+2)*(endX-startX+2))
+		for y := startY - 1; y <= endY; y++ {
+			for x := startX - 1; x <= endX; x++ {
+				slice = append(slice, world[(y+height)%height][(x+width)%width])
+			}
+		}
+		req := &stubs.WorkerRequest{
+			StartX:      startX,
+			EndX:        endX,
+			StartY:      startY,
+			EndY:        endY,
+			WorldSlice:  slice,
+			ImageWidth:  width,
+			ImageHeight: height,
+			Threads:     2,
+			TileSize:    8,
+			Turn:        turn,
+			Seed:        seed,
+			Probability: probability,
+		}
+		res := new(stubs.WorkerResponse)
+		if err := worker.CalculateNextState(req, res); err != nil {
+			t.Fatal(err)
+		}
+		for y := startY; y < endY; y++ {
+			copy(newWorld[y][startX:endX], res.WorldSlice[(y-startY)*(endX-startX):])
+		}
+	}
+	return newWorld
+}
+
+// TestStochasticReproducible checks that noisy Life gives bit-for-bit identical
+// worlds for the same seed with 1-16 workers in every block layout.
+func TestStochasticReproducible(t *testing.T) {
+	const turns = 50
+	const seed = 42
+	const probability = 0.5
+
+	expected := readWorld(t, 64)
+	for turn := 0; turn < turns; turn++ {
+		expected = stepBlocks(t, expected, 1, 1, turn, seed, probability)
+	}
+
+	for workers := 1; workers <= 16; workers++ {
+		for rows := 1; rows <= workers; rows++ {
+			if workers%rows != 0 {
+				continue
+			}
+			cols := workers / rows
+			t.Run(fmt.Sprintf("%d_workers_%dx%d", workers, rows, cols), func(t *testing.T) {
+				world := readWorld(t, 64)
+				for turn := 0; turn < turns; turn++ {
+					world = stepBlocks(t, world, rows, cols, turn, seed, probability)
+				}
+				for y := range world {
+					for x := range world[y] {
+						if world[y][x] != expected[y][x] {
+							t.Fatalf("cell (%v, %v) differs from the single worker run", x, y)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestStochasticSeeds checks that the seed actually changes a noisy run.
+func TestStochasticSeeds(t *testing.T) {
+	a, b := readWorld(t, 64), readWorld(t, 64)
+	for turn := 0; turn < 10; turn++ {
+		a = stepBlocks(t, a, 1, 1, turn, 1, 0.5)
+		b = stepBlocks(t, b, 1, 1, turn, 2, 0.5)
+	}
+	for y := range a {
+		for x := range a[y] {
+			if a[y][x] != b[y][x] {
+				return
+			}
+		}
+	}
+	t.Error("different seeds produced identical worlds")
+}
+
+// haloSlice returns the whole world wrapped in a one cell halo, as the broker
+// sends it to a single worker.
+func haloSlice(world [][]uint8) []uint8 {
+	height, width := len(world), len(world[0])
+	slice := make([]uint8, 0, (height+2)*(width+2))
+	for y := -1; y <= height; y++ {
+		for x := -1; x <= width; x++ {
+			slice = append(slice, world[(y+height)%height][(x+width)%width])
+		}
+	}
+	return slice
+}
+
+// TestConcurrentCalls has several goroutines share one GolWorker, each with
+// several threads, and checks every result against a single threaded turn.
+// Run it with -race to catch the buffer pools being shared unsafely.
+func TestConcurrentCalls(t *testing.T) {
+	slice := haloSlice(readWorld(t, 64))
+	request := func(threads int) *stubs.WorkerRequest {
+		return &stubs.WorkerRequest{
+			EndX:        64,
+			EndY:        64,
+			WorldSlice:  slice,
+			ImageWidth:  64,
+			ImageHeight: 64,
+			Threads:     threads,
+			TileSize:    8,
+		}
+	}
+	expected := new(stubs.WorkerResponse)
+	if err := new(GolWorker).CalculateNextState(request(1), expected); err != nil {
+		t.Fatal(err)
+	}
+
+	worker := new(GolWorker)
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(threads int) {
+			defer wg.Done()
+			for turn := 0; turn < 20; turn++ {
+				res := new(stubs.WorkerResponse)
+				if err := worker.CalculateNextState(request(threads), res); err != nil {
+					errs <- err
+					return
+				}
+				if !bytes.Equal(res.WorldSlice, expected.WorldSlice) || !reflect.DeepEqual(res.Changed, expected.Changed) {
+					errs <- fmt.Errorf("%d threads: result differs from a single thread", threads)
+					return
+				}
+				worker.putCells(res.WorldSlice)
+				worker.putFlags(res.Changed)
+			}
+		}(i + 2)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestBadRequests checks that empty blocks and dirty flags that do not cover
+// the block's tiles are refused instead of indexing out of range.
+func TestBadRequests(t *testing.T) {
+	for _, test := range []struct {
+		name                       string
+		startX, endX, startY, endY int
+		dirty                      []bool
+	}{
+		{"empty", 0, 0, 0, 4, nil},
+		{"negative", 4, 0, 0, 4, nil},
+		{"short dirty", 0, 16, 0, 16, []bool{true}},
+		{"long dirty", 0, 8, 0, 8, []bool{true, true}},
+	} {
+		width, height := test.endX-test.startX, test.endY-test.startY
+		size := 0
+		if width > 0 && height > 0 {
+			size = (width + 2) * (height + 2)
+		}
+		req := &stubs.WorkerRequest{
+			StartX:      test.startX,
+			EndX:        test.endX,
+			StartY:      test.startY,
+			EndY:        test.endY,
+			WorldSlice:  make([]uint8, size),
+			ImageWidth:  16,
+			ImageHeight: 16,
+			Threads:     2,
+			TileSize:    8,
+			Dirty:       test.dirty,
+		}
+		if err := new(GolWorker).CalculateNextState(req, new(stubs.WorkerResponse)); err == nil {
+			t.Errorf("%s: expected the request to be refused", test.name)
+		}
+	}
+}
+
+// BenchmarkCalculateNextState reports the allocations of one worker turn on a
+// 512x512 block once the worker's buffers have warmed up.
+func BenchmarkCalculateNextState(b *testing.B) {
+	worker := new(GolWorker)
+	req := &stubs.WorkerRequest{
+		StartX:      0,
+		EndX:        512,
+		StartY:      0,
+		EndY:        512,
+		WorldSlice:  haloSlice(readWorld(b, 512)),
+		ImageWidth:  512,
+		ImageHeight: 512,
+		Threads:     4,
+		TileSize:    32,
+	}
+	res := new(stubs.WorkerResponse)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := worker.CalculateNextState(req, res); err != nil {
+			b.Fatal(err)
+		}
+		// Hand the buffers back as the codec would after writing the response.
+		worker.putCells(res.WorldSlice)
+		worker.putFlags(res.Changed)
+	}
+}